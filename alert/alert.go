@@ -0,0 +1,184 @@
+package alert
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+type Alert struct {
+	Host      string            `json:"host"`
+	Name      string            `json:"name"`
+	Severity  string            `json:"severity"`
+	Message   string            `json:"message"`
+	Value     float64           `json:"value"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+func (a Alert) Key() string {
+	return a.Host + "/" + a.Name
+}
+
+type Notifier interface {
+	Name() string
+	Notify(alerts []Alert) error
+}
+
+type Dispatcher struct {
+	notifiers   []Notifier
+	dedupWindow time.Duration
+	rateLimit   int
+	rateWindow  time.Duration
+
+	mu sync.Mutex
+	// lastSent guarda, para cada notificador, quando cada alerta foi
+	// entregue com sucesso; falhas não entram e são reenviadas no próximo
+	// ciclo.
+	lastSent []map[string]time.Time
+	sentAt   []time.Time
+	now      func() time.Time
+}
+
+func NewDispatcher(notifiers []Notifier, dedupWindow time.Duration, rateLimit int, rateWindow time.Duration) *Dispatcher {
+	lastSent := make([]map[string]time.Time, len(notifiers))
+	for i := range lastSent {
+		lastSent[i] = make(map[string]time.Time)
+	}
+
+	return &Dispatcher{
+		notifiers:   notifiers,
+		dedupWindow: dedupWindow,
+		rateLimit:   rateLimit,
+		rateWindow:  rateWindow,
+		lastSent:    lastSent,
+		now:         time.Now,
+	}
+}
+
+func (d *Dispatcher) Dispatch(alerts []Alert) error {
+	if len(d.notifiers) == 0 || len(alerts) == 0 {
+		return nil
+	}
+
+	d.mu.Lock()
+	now := d.now()
+	pending := make([][]Alert, len(d.notifiers))
+	dropped := 0
+	for i := range d.notifiers {
+		pending[i] = d.dedup(alerts, d.lastSent[i], now)
+		dropped = max(dropped, len(pending[i]))
+	}
+	if dropped == 0 {
+		d.mu.Unlock()
+		return nil
+	}
+	if !d.allow(now) {
+		d.mu.Unlock()
+		log.Printf("Limite de notificações atingido, %d alerta(s) descartado(s)", dropped)
+		return nil
+	}
+	d.mu.Unlock()
+
+	var errs []string
+	for i, n := range d.notifiers {
+		if len(pending[i]) == 0 {
+			continue
+		}
+		if err := n.Notify(pending[i]); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", n.Name(), err))
+			continue
+		}
+
+		d.mu.Lock()
+		for _, a := range pending[i] {
+			d.lastSent[i][a.Key()] = now
+		}
+		d.mu.Unlock()
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("falha ao enviar notificações: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// dedup descarta alertas repetidos (mesmo host e nome) entregues dentro da
+// janela configurada e junta duplicatas do mesmo lote em uma única entrada.
+func (d *Dispatcher) dedup(alerts []Alert, lastSent map[string]time.Time, now time.Time) []Alert {
+	seen := make(map[string]bool)
+	var pending []Alert
+
+	for _, a := range alerts {
+		key := a.Key()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if last, ok := lastSent[key]; ok && now.Sub(last) < d.dedupWindow {
+			continue
+		}
+		pending = append(pending, a)
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return severityRank(pending[i].Severity) > severityRank(pending[j].Severity)
+	})
+
+	return pending
+}
+
+func (d *Dispatcher) allow(now time.Time) bool {
+	if d.rateLimit <= 0 {
+		return true
+	}
+
+	cutoff := now.Add(-d.rateWindow)
+	kept := d.sentAt[:0]
+	for _, t := range d.sentAt {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	d.sentAt = kept
+
+	if len(d.sentAt) >= d.rateLimit {
+		return false
+	}
+	d.sentAt = append(d.sentAt, now)
+	return true
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	}
+	return 0
+}
+
+func summary(alerts []Alert) string {
+	if len(alerts) == 1 {
+		return fmt.Sprintf("[%s] %s: %s", strings.ToUpper(alerts[0].Severity), alerts[0].Host, alerts[0].Name)
+	}
+	return fmt.Sprintf("%d alertas em %s", len(alerts), alerts[0].Host)
+}
+
+func formatText(alerts []Alert) string {
+	var b strings.Builder
+	for _, a := range alerts {
+		fmt.Fprintf(&b, "[%s] %s - %s: %s\n", strings.ToUpper(a.Severity), a.Host, a.Name, a.Message)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,150 @@
+package alert
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubNotifier struct {
+	name  string
+	fail  bool
+	sent  [][]Alert
+	calls int
+}
+
+func (n *stubNotifier) Name() string { return n.name }
+
+func (n *stubNotifier) Notify(alerts []Alert) error {
+	n.calls++
+	if n.fail {
+		return errors.New("indisponível")
+	}
+	n.sent = append(n.sent, alerts)
+	return nil
+}
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestDispatcher(rateLimit int, notifiers ...Notifier) (*Dispatcher, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	d := NewDispatcher(notifiers, 5*time.Minute, rateLimit, time.Hour)
+	d.now = clock.now
+	return d, clock
+}
+
+func testAlert(name, severity string) Alert {
+	return Alert{Host: "srv01", Name: name, Severity: severity}
+}
+
+func TestDispatchDedup(t *testing.T) {
+	n := &stubNotifier{name: "stub"}
+	d, clock := newTestDispatcher(0, n)
+
+	cpu := testAlert("cpu_usage", SeverityWarning)
+	disk := testAlert("disk_usage", SeverityCritical)
+	if err := d.Dispatch([]Alert{cpu, disk, cpu}); err != nil {
+		t.Fatal(err)
+	}
+	if len(n.sent) != 1 || len(n.sent[0]) != 2 {
+		t.Fatalf("esperado um lote com 2 alertas, obtido %v", n.sent)
+	}
+	if n.sent[0][0].Name != "disk_usage" {
+		t.Errorf("esperado alerta crítico primeiro, obtido %s", n.sent[0][0].Name)
+	}
+
+	clock.advance(time.Minute)
+	if err := d.Dispatch([]Alert{cpu}); err != nil {
+		t.Fatal(err)
+	}
+	if n.calls != 1 {
+		t.Errorf("alerta repetido dentro da janela não deveria ser enviado")
+	}
+
+	clock.advance(5 * time.Minute)
+	if err := d.Dispatch([]Alert{cpu}); err != nil {
+		t.Fatal(err)
+	}
+	if n.calls != 2 {
+		t.Errorf("esperado reenvio após a janela, obtido %d chamada(s)", n.calls)
+	}
+}
+
+func TestDispatchRetriesFailedSend(t *testing.T) {
+	n := &stubNotifier{name: "stub", fail: true}
+	d, clock := newTestDispatcher(0, n)
+	cpu := testAlert("cpu_usage", SeverityWarning)
+
+	if err := d.Dispatch([]Alert{cpu}); err == nil {
+		t.Fatal("esperado erro do notificador")
+	}
+
+	// A falha não conta como envio: o próximo ciclo tenta de novo mesmo
+	// dentro da janela de deduplicação.
+	n.fail = false
+	clock.advance(time.Minute)
+	if err := d.Dispatch([]Alert{cpu}); err != nil {
+		t.Fatal(err)
+	}
+	if len(n.sent) != 1 {
+		t.Fatalf("esperado reenvio após falha, obtido %d lote(s)", len(n.sent))
+	}
+
+	clock.advance(time.Minute)
+	if err := d.Dispatch([]Alert{cpu}); err != nil {
+		t.Fatal(err)
+	}
+	if n.calls != 2 {
+		t.Errorf("alerta entregue não deveria ser reenviado dentro da janela, obtido %d chamada(s)", n.calls)
+	}
+}
+
+func TestDispatchRetriesOnlyFailedNotifier(t *testing.T) {
+	ok := &stubNotifier{name: "webhook"}
+	failing := &stubNotifier{name: "email", fail: true}
+	d, clock := newTestDispatcher(0, ok, failing)
+	cpu := testAlert("cpu_usage", SeverityWarning)
+
+	if err := d.Dispatch([]Alert{cpu}); err == nil {
+		t.Fatal("esperado erro do notificador de email")
+	}
+
+	failing.fail = false
+	clock.advance(time.Minute)
+	if err := d.Dispatch([]Alert{cpu}); err != nil {
+		t.Fatal(err)
+	}
+	if ok.calls != 1 || failing.calls != 2 || len(failing.sent) != 1 {
+		t.Errorf("esperado reenvio só pelo email, obtido webhook=%d email=%d", ok.calls, failing.calls)
+	}
+}
+
+func TestDispatchRateLimit(t *testing.T) {
+	n := &stubNotifier{name: "stub"}
+	d, clock := newTestDispatcher(1, n)
+
+	if err := d.Dispatch([]Alert{testAlert("cpu_usage", SeverityWarning)}); err != nil {
+		t.Fatal(err)
+	}
+	clock.advance(time.Minute)
+	if err := d.Dispatch([]Alert{testAlert("disk_usage", SeverityCritical)}); err != nil {
+		t.Fatal(err)
+	}
+	if n.calls != 1 {
+		t.Errorf("esperado envio bloqueado pelo limite, obtido %d chamada(s)", n.calls)
+	}
+
+	clock.advance(time.Hour)
+	if err := d.Dispatch([]Alert{testAlert("disk_usage", SeverityCritical)}); err != nil {
+		t.Fatal(err)
+	}
+	if n.calls != 2 {
+		t.Errorf("esperado envio após a janela do limite, obtido %d chamada(s)", n.calls)
+	}
+}
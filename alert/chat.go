@@ -0,0 +1,64 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type SlackNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewSlackNotifier(url string) *SlackNotifier {
+	return &SlackNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *SlackNotifier) Name() string {
+	return "slack"
+}
+
+func (s *SlackNotifier) Notify(alerts []Alert) error {
+	body, err := json.Marshal(map[string]string{
+		"text": "*" + summary(alerts) + "*\n" + formatText(alerts),
+	})
+	if err != nil {
+		return err
+	}
+	return post(s.Client, s.URL, body)
+}
+
+type TeamsNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewTeamsNotifier(url string) *TeamsNotifier {
+	return &TeamsNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (t *TeamsNotifier) Name() string {
+	return "teams"
+}
+
+func (t *TeamsNotifier) Notify(alerts []Alert) error {
+	color := "FFA500"
+	if severityRank(alerts[0].Severity) >= severityRank(SeverityCritical) {
+		color = "FF0000"
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    summary(alerts),
+		"title":      summary(alerts),
+		"themeColor": color,
+		"text":       strings.ReplaceAll(formatText(alerts), "\n", "\n\n"),
+	})
+	if err != nil {
+		return err
+	}
+	return post(t.Client, t.URL, body)
+}
@@ -0,0 +1,45 @@
+package alert
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type EmailNotifier struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+func (e *EmailNotifier) Name() string {
+	return "email"
+}
+
+func (e *EmailNotifier) Notify(alerts []Alert) error {
+	var auth smtp.Auth
+	if e.Username != "" {
+		auth = smtp.PlainAuth("", e.Username, e.Password, e.Host)
+	}
+
+	return smtp.SendMail(net.JoinHostPort(e.Host, e.Port), auth, e.From, e.To, e.message(alerts))
+}
+
+func (e *EmailNotifier) message(alerts []Alert) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", summary(alerts))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(formatText(alerts), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
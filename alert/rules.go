@@ -0,0 +1,220 @@
+package alert
+
+import (
+	"fmt"
+	"time"
+
+	"monitoramento/anomaly"
+	"monitoramento/hardware"
+	"monitoramento/software"
+	"monitoramento/watchdog"
+)
+
+func CheckDisks(host string, disks []hardware.DiskInfo, threshold float64) []Alert {
+	var alerts []Alert
+
+	for _, d := range disks {
+		labels := map[string]string{"device": d.Device, "mountpoint": d.Mountpoint, "type": d.Type}
+
+		if d.RemountedReadOnly {
+			alerts = append(alerts, Alert{
+				Host:      host,
+				Name:      "disk_readonly:" + d.Mountpoint,
+				Severity:  SeverityCritical,
+				Message:   fmt.Sprintf("%s (%s) foi remontado como somente leitura por erro", d.Mountpoint, d.Device),
+				Labels:    labels,
+				Timestamp: time.Now(),
+			})
+		}
+
+		if threshold <= 0 {
+			continue
+		}
+
+		if d.UsagePercent >= threshold {
+			alerts = append(alerts, Alert{
+				Host:      host,
+				Name:      "disk_full:" + d.Mountpoint,
+				Severity:  usageSeverity(d.UsagePercent),
+				Message:   fmt.Sprintf("%s (%s) com %.1f%% de uso (limite %.0f%%)", d.Mountpoint, d.Device, d.UsagePercent, threshold),
+				Value:     d.UsagePercent,
+				Labels:    labels,
+				Timestamp: time.Now(),
+			})
+		}
+
+		if d.InodesPercent >= threshold {
+			alerts = append(alerts, Alert{
+				Host:      host,
+				Name:      "disk_inodes:" + d.Mountpoint,
+				Severity:  usageSeverity(d.InodesPercent),
+				Message:   fmt.Sprintf("%s (%s) com %.1f%% dos inodes em uso (limite %.0f%%)", d.Mountpoint, d.Device, d.InodesPercent, threshold),
+				Value:     d.InodesPercent,
+				Labels:    labels,
+				Timestamp: time.Now(),
+			})
+		}
+	}
+
+	return alerts
+}
+
+func usageSeverity(percent float64) string {
+	if percent >= 98 {
+		return SeverityCritical
+	}
+	return SeverityWarning
+}
+
+func CheckAnomalies(host string, scores []anomaly.Score) []Alert {
+	var alerts []Alert
+	for _, s := range scores {
+		if !s.Anomalous {
+			continue
+		}
+
+		alerts = append(alerts, Alert{
+			Host:      host,
+			Name:      "anomaly:" + s.Metric,
+			Severity:  SeverityWarning,
+			Message:   fmt.Sprintf("%s = %.2f fora do normal (média %.2f, desvio %.2f, score %.1f)", s.Metric, s.Value, s.Mean, s.StdDev, s.Score),
+			Value:     s.Score,
+			Labels:    map[string]string{"metric": s.Metric, "baseline": s.Baseline},
+			Timestamp: time.Now(),
+		})
+	}
+
+	return alerts
+}
+
+func CheckUSBEvent(host string, event hardware.USBEvent) []Alert {
+	if event.Verdict != hardware.USBVerdictDenied || event.Action != "add" {
+		return nil
+	}
+
+	dev := event.Device
+	return []Alert{{
+		Host:     host,
+		Name:     "usb_denied:" + dev.VendorID + ":" + dev.ProductID + ":" + dev.SerialNumber,
+		Severity: SeverityCritical,
+		Message:  fmt.Sprintf("Dispositivo USB não autorizado conectado na porta %s: %s (%s:%s, classe %s, regra %s)", dev.Path, dev.Name, dev.VendorID, dev.ProductID, dev.ClassName, event.Rule),
+		Labels: map[string]string{
+			"vendor_id":  dev.VendorID,
+			"product_id": dev.ProductID,
+			"serial":     dev.SerialNumber,
+			"class":      dev.Class,
+			"port_path":  dev.Path,
+		},
+		Timestamp: event.Timestamp,
+	}}
+}
+
+func CheckDiskHealth(host string, disks []hardware.PhysicalDisk) []Alert {
+	var alerts []Alert
+
+	add := func(disk hardware.PhysicalDisk, check, severity, message string, value float64) {
+		alerts = append(alerts, Alert{
+			Host:      host,
+			Name:      "disk_health:" + check + ":" + disk.Name,
+			Severity:  severity,
+			Message:   fmt.Sprintf("Disco %s (%s, serial %s): %s", disk.Name, disk.Model, disk.Serial, message),
+			Value:     value,
+			Labels:    map[string]string{"device": disk.Name, "model": disk.Model, "serial": disk.Serial},
+			Timestamp: time.Now(),
+		})
+	}
+
+	for _, disk := range disks {
+		health := disk.Health
+		if health == nil {
+			continue
+		}
+
+		// Sem resultado do autoteste não há como dizer que reprovou.
+		if health.Passed != nil && !*health.Passed {
+			add(disk, "smart_failed", SeverityCritical, "autoteste SMART reprovado", 0)
+		}
+
+		for _, attr := range health.Attributes {
+			// 5 = setores realocados, 197 = pendentes, 198 = incorrigíveis
+			if (attr.ID == 5 || attr.ID == 197 || attr.ID == 198) && attr.Raw > 0 {
+				add(disk, fmt.Sprintf("smart_%d", attr.ID), SeverityWarning, fmt.Sprintf("%s = %d", attr.Name, attr.Raw), float64(attr.Raw))
+			}
+		}
+
+		if nvme := health.NVMe; nvme != nil {
+			if nvme.PercentageUsed >= 90 {
+				add(disk, "nvme_wear", SeverityWarning, fmt.Sprintf("%d%% da vida útil consumida", nvme.PercentageUsed), float64(nvme.PercentageUsed))
+			}
+			if nvme.MediaErrors > 0 {
+				add(disk, "nvme_media_errors", SeverityWarning, fmt.Sprintf("%d erros de mídia", nvme.MediaErrors), float64(nvme.MediaErrors))
+			}
+		}
+	}
+
+	return alerts
+}
+
+func CheckWatchdog(host string, statuses []watchdog.Status) []Alert {
+	var alerts []Alert
+
+	for _, st := range statuses {
+		if st.Up {
+			continue
+		}
+
+		message := fmt.Sprintf("Processo obrigatório %s não está rodando desde %s", st.Name, st.DownSince.Format("2006-01-02 15:04:05"))
+		if st.Restarts > 0 {
+			message += fmt.Sprintf(" (%d tentativas de reinício", st.Restarts)
+			if st.LastError != "" {
+				message += ", última falhou: " + st.LastError
+			}
+			message += ")"
+		}
+
+		alerts = append(alerts, Alert{
+			Host:      host,
+			Name:      "process_down:" + st.Name,
+			Severity:  SeverityCritical,
+			Message:   message,
+			Value:     float64(st.Restarts),
+			Labels:    map[string]string{"process": st.Name},
+			Timestamp: time.Now(),
+		})
+	}
+
+	return alerts
+}
+
+func CheckServices(host string, services []software.Service) []Alert {
+	var alerts []Alert
+
+	for _, svc := range services {
+		if !svc.Failed {
+			continue
+		}
+
+		message := fmt.Sprintf("Serviço %s falhou", svc.Name)
+		if svc.SubState != "" && svc.SubState != "failed" {
+			message += " (" + svc.SubState + ")"
+		}
+		if !svc.StateChange.IsZero() {
+			message += " em " + svc.StateChange.Format("2006-01-02 15:04:05")
+		}
+		if svc.Restarts > 0 {
+			message += fmt.Sprintf(", após %d reinícios", svc.Restarts)
+		}
+
+		alerts = append(alerts, Alert{
+			Host:      host,
+			Name:      "service_failed:" + svc.Name,
+			Severity:  SeverityWarning,
+			Message:   message,
+			Value:     float64(svc.Restarts),
+			Labels:    map[string]string{"service": svc.Name},
+			Timestamp: time.Now(),
+		})
+	}
+
+	return alerts
+}
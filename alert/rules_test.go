@@ -0,0 +1,22 @@
+package alert
+
+import (
+	"testing"
+
+	"monitoramento/hardware"
+)
+
+func TestCheckDiskHealthSMARTStatus(t *testing.T) {
+	passed, failed := true, false
+	disks := []hardware.PhysicalDisk{
+		{Name: "sda", Health: &hardware.DiskHealth{Passed: &passed}},
+		{Name: "sdb", Health: &hardware.DiskHealth{Passed: &failed}},
+		// Ponte USB sem resultado do autoteste.
+		{Name: "sdc", Health: &hardware.DiskHealth{}},
+	}
+
+	alerts := CheckDiskHealth("srv01", disks)
+	if len(alerts) != 1 || alerts[0].Name != "disk_health:smart_failed:sdb" || alerts[0].Severity != SeverityCritical {
+		t.Errorf("esperado só o alerta de sdb, obtido %+v", alerts)
+	}
+}
@@ -0,0 +1,87 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"text/template"
+	"time"
+)
+
+const defaultWebhookTemplate = `{"summary": {{json .Summary}}, "alerts": {{json .Alerts}}}`
+
+type WebhookNotifier struct {
+	URL      string
+	Template *template.Template
+	Client   *http.Client
+}
+
+type webhookData struct {
+	Summary string
+	Text    string
+	Alerts  []Alert
+}
+
+func NewWebhookNotifier(url, templateFile string) (*WebhookNotifier, error) {
+	text := defaultWebhookTemplate
+	if templateFile != "" {
+		content, err := os.ReadFile(templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler template do webhook: %v", err)
+		}
+		text = string(content)
+	}
+
+	tmpl, err := template.New("webhook").Funcs(template.FuncMap{"json": toJSON}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar template do webhook: %v", err)
+	}
+
+	return &WebhookNotifier{
+		URL:      url,
+		Template: tmpl,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (w *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (w *WebhookNotifier) Notify(alerts []Alert) error {
+	var body bytes.Buffer
+	err := w.Template.Execute(&body, webhookData{
+		Summary: summary(alerts),
+		Text:    formatText(alerts),
+		Alerts:  alerts,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao gerar corpo do webhook: %v", err)
+	}
+
+	return post(w.Client, w.URL, body.Bytes())
+}
+
+func toJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func post(client *http.Client, url string, body []byte) error {
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook retornou status não-OK: %v", resp.Status)
+	}
+
+	return nil
+}
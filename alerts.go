@@ -0,0 +1,62 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"monitoramento/alert"
+)
+
+func newAlertDispatcher(config map[string]string) *alert.Dispatcher {
+	var notifiers []alert.Notifier
+
+	if url := config["alert_webhook_url"]; url != "" {
+		webhook, err := alert.NewWebhookNotifier(url, config["alert_webhook_template"])
+		if err != nil {
+			log.Printf("Erro ao configurar notificação via webhook: %v", err)
+		} else {
+			notifiers = append(notifiers, webhook)
+		}
+	}
+
+	if url := config["alert_slack_webhook_url"]; url != "" {
+		notifiers = append(notifiers, alert.NewSlackNotifier(url))
+	}
+
+	if url := config["alert_teams_webhook_url"]; url != "" {
+		notifiers = append(notifiers, alert.NewTeamsNotifier(url))
+	}
+
+	if host := config["alert_smtp_host"]; host != "" {
+		port := config["alert_smtp_port"]
+		if port == "" {
+			port = "25"
+		}
+		notifiers = append(notifiers, &alert.EmailNotifier{
+			Host:     host,
+			Port:     port,
+			Username: config["alert_smtp_username"],
+			Password: config["alert_smtp_password"],
+			From:     config["alert_smtp_from"],
+			To:       splitList(config["alert_smtp_to"]),
+		})
+	}
+
+	dedupWindow := configSeconds(config, "alert_dedup_window", time.Hour)
+	rateLimit := configInt(config, "alert_rate_limit", 10)
+
+	return alert.NewDispatcher(notifiers, dedupWindow, rateLimit, time.Hour)
+}
+
+func evaluateAlerts(config map[string]string, info SystemInfo) []alert.Alert {
+	host := info.Software.OS.Hostname
+	threshold := configFloat(config, "alert_disk_threshold", 90)
+
+	alerts := alert.CheckDisks(host, info.Hardware.Disk, threshold)
+	alerts = append(alerts, alert.CheckDiskHealth(host, info.Hardware.PhysicalDisks)...)
+	alerts = append(alerts, alert.CheckAnomalies(host, info.Anomalies)...)
+	alerts = append(alerts, alert.CheckWatchdog(host, info.Watchdog)...)
+	alerts = append(alerts, alert.CheckServices(host, info.Software.SystemServices)...)
+
+	return alerts
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"monitoramento/anomaly"
+)
+
+func newAnomalyDetector(config map[string]string) *anomaly.Detector {
+	detector := anomaly.NewDetector(
+		configFloat(config, "anomaly_threshold", 3),
+		configFloat(config, "anomaly_ewma_alpha", 0.1),
+		configInt(config, "anomaly_min_samples", 10),
+	)
+
+	if stateFile := config["anomaly_state_file"]; stateFile != "" {
+		if err := detector.Load(stateFile); err != nil && !os.IsNotExist(err) {
+			log.Printf("Erro ao carregar linha de base de anomalias: %v", err)
+		}
+	}
+
+	return detector
+}
@@ -0,0 +1,162 @@
+package anomaly
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"time"
+
+	"monitoramento/performance"
+)
+
+const hoursPerWeek = 7 * 24
+
+type Score struct {
+	Metric    string  `json:"metric"`
+	Value     float64 `json:"value"`
+	Mean      float64 `json:"mean"`
+	StdDev    float64 `json:"stddev"`
+	Score     float64 `json:"score"`
+	Baseline  string  `json:"baseline"`
+	Anomalous bool    `json:"anomalous"`
+}
+
+// EWMA mantém média e variância com decaimento exponencial.
+type EWMA struct {
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	Count    int     `json:"count"`
+}
+
+func (e *EWMA) Update(value, alpha float64) {
+	if e.Count == 0 {
+		e.Mean = value
+		e.Variance = 0
+		e.Count = 1
+		return
+	}
+	diff := value - e.Mean
+	incr := alpha * diff
+	e.Mean += incr
+	e.Variance = (1 - alpha) * (e.Variance + diff*incr)
+	e.Count++
+}
+
+func (e *EWMA) StdDev() float64 {
+	return math.Sqrt(e.Variance)
+}
+
+// Stats acumula média e desvio padrão pelo algoritmo de Welford.
+type Stats struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean"`
+	M2    float64 `json:"m2"`
+}
+
+func (s *Stats) Update(value float64) {
+	s.Count++
+	delta := value - s.Mean
+	s.Mean += delta / float64(s.Count)
+	s.M2 += delta * (value - s.Mean)
+}
+
+func (s *Stats) StdDev() float64 {
+	if s.Count < 2 {
+		return 0
+	}
+	return math.Sqrt(s.M2 / float64(s.Count-1))
+}
+
+type Baseline struct {
+	EWMA       EWMA                `json:"ewma"`
+	HourOfWeek [hoursPerWeek]Stats `json:"hour_of_week"`
+}
+
+type Detector struct {
+	Threshold  float64              `json:"-"`
+	Alpha      float64              `json:"-"`
+	MinSamples int                  `json:"-"`
+	Baselines  map[string]*Baseline `json:"baselines"`
+}
+
+func NewDetector(threshold, alpha float64, minSamples int) *Detector {
+	return &Detector{
+		Threshold:  threshold,
+		Alpha:      alpha,
+		MinSamples: minSamples,
+		Baselines:  make(map[string]*Baseline),
+	}
+}
+
+func (d *Detector) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, d)
+}
+
+func (d *Detector) Save(path string) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func (d *Detector) Observe(metrics performance.Metrics, at time.Time) []Score {
+	samples := []struct {
+		name  string
+		value float64
+	}{
+		{"cpu_usage_percent", metrics.CPUUsage},
+		{"memory_usage_percent", metrics.MemoryUsage},
+		{"disk_io_bytes_per_sec", float64(metrics.DiskIO.ReadBytes + metrics.DiskIO.WriteBytes)},
+		{"network_io_bytes_per_sec", float64(metrics.NetworkIO.BytesSent + metrics.NetworkIO.BytesRecv)},
+	}
+
+	var scores []Score
+	for _, s := range samples {
+		scores = append(scores, d.observe(s.name, s.value, at))
+	}
+	return scores
+}
+
+// observe pontua a amostra contra a linha de base antes de incorporá-la,
+// preferindo a estatística da hora da semana quando já há amostras suficientes.
+func (d *Detector) observe(metric string, value float64, at time.Time) Score {
+	if d.Baselines == nil {
+		d.Baselines = make(map[string]*Baseline)
+	}
+	b, ok := d.Baselines[metric]
+	if !ok {
+		b = &Baseline{}
+		d.Baselines[metric] = b
+	}
+
+	hour := &b.HourOfWeek[int(at.Weekday())*24+at.Hour()]
+	score := Score{Metric: metric, Value: value}
+
+	switch {
+	case hour.Count >= d.MinSamples:
+		score.Baseline = "hour_of_week"
+		score.Mean = hour.Mean
+		score.StdDev = hour.StdDev()
+	case b.EWMA.Count >= d.MinSamples:
+		score.Baseline = "ewma"
+		score.Mean = b.EWMA.Mean
+		score.StdDev = b.EWMA.StdDev()
+	default:
+		score.Baseline = "learning"
+	}
+
+	if score.Baseline != "learning" && score.StdDev > 0 {
+		score.Score = (value - score.Mean) / score.StdDev
+		score.Anomalous = math.Abs(score.Score) >= d.Threshold
+	}
+
+	hour.Update(value)
+	b.EWMA.Update(value, d.Alpha)
+
+	return score
+}
@@ -0,0 +1,51 @@
+// Comando sbom: coleta o inventário de software da máquina e imprime (ou
+// grava) o SBOM, sem depender do servidor nem do config.ini.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"monitoramento/sbom"
+	"monitoramento/software"
+)
+
+func main() {
+	format := flag.String("format", sbom.FormatCycloneDX, "formato do documento: cyclonedx ou spdx")
+	output := flag.String("o", "", "arquivo de saída (padrão: saída padrão)")
+	python := flag.Bool("python", false, "incluir pacotes Python")
+	npm := flag.Bool("npm", false, "incluir pacotes npm globais")
+	flag.Parse()
+
+	if *format != sbom.FormatCycloneDX && *format != sbom.FormatSPDX {
+		log.Fatalf("Formato de SBOM desconhecido: %s", *format)
+	}
+
+	info := software.Collect(software.Options{
+		Ecosystems: software.EcosystemOptions{Python: *python, NPM: *npm},
+	})
+
+	report, err := sbom.Generate(info, []string{*format}, time.Now())
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	var doc any = report.CycloneDX
+	if report.SPDX != nil {
+		doc = report.SPDX
+	}
+	data, err := sbom.Marshal(doc)
+	if err != nil {
+		log.Fatalf("Erro ao gerar o SBOM: %v", err)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		log.Fatalf("Erro ao gravar o SBOM: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"monitoramento/hardware"
+	"monitoramento/software"
+)
+
+func newHardwareOptions(config map[string]string) hardware.Options {
+	return hardware.Options{
+		Filesystems: hardware.FilesystemFilter{
+			IncludePseudo: config["disk_include_pseudo"] == "true",
+			IncludeTypes:  splitList(config["disk_include_types"]),
+			ExcludeTypes:  splitList(config["disk_exclude_types"]),
+			IncludePaths:  splitList(config["disk_include_paths"]),
+			ExcludePaths:  splitList(config["disk_exclude_paths"]),
+		},
+	}
+}
+
+func newSoftwareOptions(config map[string]string) software.Options {
+	return software.Options{
+		Processes: software.ProcessOptions{
+			TopCPU:      configInt(config, "process_top_cpu", 0),
+			TopMemory:   configInt(config, "process_top_memory", 0),
+			TopIO:       configInt(config, "process_top_io", 0),
+			TopFDs:      configInt(config, "process_top_fds", 0),
+			TopNetwork:  configInt(config, "process_top_network", 0),
+			GroupByName: config["process_group_by_name"] == "true",
+			Include:     splitList(config["process_include"]),
+			Exclude:     splitList(config["process_exclude"]),
+		},
+		Ecosystems: software.EcosystemOptions{
+			Python:      config["ecosystem_python"] == "true",
+			PythonPaths: splitList(config["ecosystem_python_paths"]),
+			NPM:         config["ecosystem_npm"] == "true",
+			NPMPaths:    splitList(config["ecosystem_npm_paths"]),
+			GoPaths:     splitList(config["ecosystem_go_paths"]),
+			JARPaths:    splitList(config["ecosystem_jar_paths"]),
+		},
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func configInt(config map[string]string, key string, def int) int {
+	value, ok := config[key]
+	if !ok || value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Valor inválido para %s: %q", key, value)
+		return def
+	}
+	return n
+}
+
+func configFloat(config map[string]string, key string, def float64) float64 {
+	value, ok := config[key]
+	if !ok || value == "" {
+		return def
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Valor inválido para %s: %q", key, value)
+		return def
+	}
+	return n
+}
+
+func configSeconds(config map[string]string, key string, def time.Duration) time.Duration {
+	return time.Duration(configInt(config, key, int(def/time.Second))) * time.Second
+}
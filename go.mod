@@ -3,8 +3,8 @@ module monitoramento
 go 1.23.3
 
 require (
+	github.com/godbus/dbus/v5 v5.1.0
 	github.com/jaypipes/ghw v0.10.0
-	github.com/shirou/gopsutil v3.21.11+incompatible
 	github.com/shirou/gopsutil/v3 v3.23.4
 	golang.org/x/sys v0.7.0
 )
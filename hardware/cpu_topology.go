@@ -0,0 +1,336 @@
+package hardware
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type CPUFrequency struct {
+	CPU      int     `json:"cpu"`
+	CoreType string  `json:"core_type,omitempty"`
+	MinMHz   float64 `json:"min_mhz"`
+	MaxMHz   float64 `json:"max_mhz"`
+	CurMHz   float64 `json:"current_mhz"`
+}
+
+type CPUCache struct {
+	Level     int    `json:"level"`
+	Type      string `json:"type"`
+	SizeBytes uint64 `json:"size_bytes"`
+	Instances int    `json:"instances"`
+}
+
+type NUMANode struct {
+	ID          int    `json:"id"`
+	CPUs        []int  `json:"cpus"`
+	MemoryBytes uint64 `json:"memory_bytes"`
+}
+
+type cpuTopology struct {
+	Sockets          int
+	PhysicalCores    int
+	LogicalThreads   int
+	PerformanceCores int
+	EfficiencyCores  int
+	Frequencies      []CPUFrequency
+	Caches           []CPUCache
+	NUMANodes        []NUMANode
+	Flags            []string
+}
+
+func readCPUTopology(root string) (cpuTopology, error) {
+	processors, err := parseCPUInfo(filepath.Join(root, "proc/cpuinfo"))
+	if err != nil {
+		return cpuTopology{}, err
+	}
+
+	var topo cpuTopology
+	if len(processors) > 0 {
+		flags := processors[0]["flags"]
+		if flags == "" {
+			flags = processors[0]["Features"]
+		}
+		topo.Flags = strings.Fields(flags)
+	}
+
+	cpuDir := filepath.Join(root, "sys/devices/system/cpu")
+	cpus := onlineCPUs(cpuDir)
+	if len(cpus) == 0 {
+		topoFromCPUInfo(&topo, processors)
+		if topo.LogicalThreads == 0 {
+			return cpuTopology{}, fmt.Errorf("nenhum processador encontrado em %s", root)
+		}
+		return topo, nil
+	}
+
+	coreTypes := hybridCoreTypes(root)
+	byProcessor := make(map[string]map[string]string)
+	for _, p := range processors {
+		byProcessor[p["processor"]] = p
+	}
+
+	sockets := make(map[string]bool)
+	cores := make(map[string]string)
+	caches := make(map[string]*CPUCache)
+	cacheInstances := make(map[string]bool)
+
+	for _, cpu := range cpus {
+		dir := filepath.Join(cpuDir, fmt.Sprintf("cpu%d", cpu))
+		info := byProcessor[strconv.Itoa(cpu)]
+
+		pkg := readString(filepath.Join(dir, "topology/physical_package_id"))
+		if pkg == "" {
+			pkg = info["physical id"]
+		}
+
+		// Os irmãos de SMT compartilham a mesma lista core_cpus_list, o que
+		// identifica o núcleo físico mesmo quando core_id se repete entre
+		// sockets ou dies.
+		core := readString(filepath.Join(dir, "topology/core_cpus_list"))
+		if core == "" {
+			core = readString(filepath.Join(dir, "topology/thread_siblings_list"))
+		}
+		if core == "" {
+			coreID := readString(filepath.Join(dir, "topology/core_id"))
+			if coreID == "" {
+				coreID = info["core id"]
+			}
+			if coreID == "" {
+				coreID = strconv.Itoa(cpu)
+			}
+			core = pkg + ":" + coreID
+		}
+
+		sockets[pkg] = true
+		if _, ok := cores[core]; !ok {
+			cores[core] = coreTypes[cpu]
+		}
+
+		freq := CPUFrequency{CPU: cpu, CoreType: coreTypes[cpu]}
+		if khz, ok := readUint(filepath.Join(dir, "cpufreq/cpuinfo_min_freq")); ok {
+			freq.MinMHz = float64(khz) / 1000
+		}
+		if khz, ok := readUint(filepath.Join(dir, "cpufreq/cpuinfo_max_freq")); ok {
+			freq.MaxMHz = float64(khz) / 1000
+		}
+		if khz, ok := readUint(filepath.Join(dir, "cpufreq/scaling_cur_freq")); ok {
+			freq.CurMHz = float64(khz) / 1000
+		} else if mhz, err := strconv.ParseFloat(info["cpu MHz"], 64); err == nil {
+			freq.CurMHz = mhz
+		}
+		topo.Frequencies = append(topo.Frequencies, freq)
+
+		indexes, _ := filepath.Glob(filepath.Join(dir, "cache/index*"))
+		for _, index := range indexes {
+			level, _ := strconv.Atoi(readString(filepath.Join(index, "level")))
+			cacheType := readString(filepath.Join(index, "type"))
+			size := readString(filepath.Join(index, "size"))
+			// O tamanho entra na chave porque em CPUs híbridas o L2 dos
+			// núcleos P e o dos núcleos E são caches diferentes.
+			key := fmt.Sprintf("L%d %s %s", level, cacheType, size)
+
+			instance := key + " " + readString(filepath.Join(index, "shared_cpu_list"))
+			if cacheInstances[instance] {
+				continue
+			}
+			cacheInstances[instance] = true
+
+			cache, ok := caches[key]
+			if !ok {
+				cache = &CPUCache{
+					Level:     level,
+					Type:      cacheType,
+					SizeBytes: parseCacheSize(size),
+				}
+				caches[key] = cache
+			}
+			cache.Instances++
+		}
+	}
+
+	topo.Sockets = len(sockets)
+	topo.PhysicalCores = len(cores)
+	topo.LogicalThreads = len(cpus)
+	for _, coreType := range cores {
+		switch coreType {
+		case "performance":
+			topo.PerformanceCores++
+		case "efficiency":
+			topo.EfficiencyCores++
+		}
+	}
+
+	for _, cache := range caches {
+		topo.Caches = append(topo.Caches, *cache)
+	}
+	sort.Slice(topo.Caches, func(i, j int) bool {
+		if topo.Caches[i].Level != topo.Caches[j].Level {
+			return topo.Caches[i].Level < topo.Caches[j].Level
+		}
+		if topo.Caches[i].Type != topo.Caches[j].Type {
+			return topo.Caches[i].Type < topo.Caches[j].Type
+		}
+		return topo.Caches[i].SizeBytes < topo.Caches[j].SizeBytes
+	})
+
+	topo.NUMANodes = readNUMANodes(filepath.Join(root, "sys/devices/system/node"))
+
+	return topo, nil
+}
+
+func parseCPUInfo(path string) ([]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var processors []map[string]string
+	current := make(map[string]string)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			if _, ok := current["processor"]; ok {
+				processors = append(processors, current)
+			}
+			current = make(map[string]string)
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		current[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	if _, ok := current["processor"]; ok {
+		processors = append(processors, current)
+	}
+
+	return processors, scanner.Err()
+}
+
+func topoFromCPUInfo(topo *cpuTopology, processors []map[string]string) {
+	sockets := make(map[string]bool)
+	cores := make(map[string]bool)
+
+	for _, p := range processors {
+		pkg := p["physical id"]
+		coreID, ok := p["core id"]
+		if !ok {
+			coreID = p["processor"]
+		}
+		sockets[pkg] = true
+		cores[pkg+":"+coreID] = true
+
+		if mhz, err := strconv.ParseFloat(p["cpu MHz"], 64); err == nil {
+			cpu, _ := strconv.Atoi(p["processor"])
+			topo.Frequencies = append(topo.Frequencies, CPUFrequency{CPU: cpu, CurMHz: mhz})
+		}
+	}
+
+	topo.Sockets = len(sockets)
+	topo.PhysicalCores = len(cores)
+	topo.LogicalThreads = len(processors)
+}
+
+func onlineCPUs(cpuDir string) []int {
+	if cpus := parseCPUList(readString(filepath.Join(cpuDir, "online"))); len(cpus) > 0 {
+		return cpus
+	}
+
+	dirs, _ := filepath.Glob(filepath.Join(cpuDir, "cpu[0-9]*"))
+	var cpus []int
+	for _, dir := range dirs {
+		if cpu, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "cpu")); err == nil {
+			cpus = append(cpus, cpu)
+		}
+	}
+	sort.Ints(cpus)
+	return cpus
+}
+
+// hybridCoreTypes usa as PMUs cpu_core e cpu_atom que o kernel expõe em
+// processadores híbridos da Intel para separar núcleos P e E.
+func hybridCoreTypes(root string) map[int]string {
+	types := make(map[int]string)
+	for pmu, coreType := range map[string]string{"cpu_core": "performance", "cpu_atom": "efficiency"} {
+		for _, cpu := range parseCPUList(readString(filepath.Join(root, "sys/devices", pmu, "cpus"))) {
+			types[cpu] = coreType
+		}
+	}
+	return types
+}
+
+func readNUMANodes(nodeDir string) []NUMANode {
+	dirs, _ := filepath.Glob(filepath.Join(nodeDir, "node[0-9]*"))
+
+	var nodes []NUMANode
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+
+		node := NUMANode{
+			ID:   id,
+			CPUs: parseCPUList(readString(filepath.Join(dir, "cpulist"))),
+		}
+
+		// Formato: "Node 0 MemTotal:       16314552 kB"
+		for _, line := range strings.Split(readString(filepath.Join(dir, "meminfo")), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) >= 4 && fields[2] == "MemTotal:" {
+				kb, _ := strconv.ParseUint(fields[3], 10, 64)
+				node.MemoryBytes = kb * 1024
+				break
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+func parseCacheSize(size string) uint64 {
+	if size == "" {
+		return 0
+	}
+
+	multiplier := uint64(1)
+	switch size[len(size)-1] {
+	case 'K':
+		multiplier = 1024
+	case 'M':
+		multiplier = 1024 * 1024
+	case 'G':
+		multiplier = 1024 * 1024 * 1024
+	}
+	n, err := strconv.ParseUint(strings.TrimRight(size, "KMG"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n * multiplier
+}
+
+func virtualizationSupport(flags []string) string {
+	for _, flag := range flags {
+		switch flag {
+		case "vmx":
+			return "VT-x"
+		case "svm":
+			return "AMD-V"
+		}
+	}
+	return ""
+}
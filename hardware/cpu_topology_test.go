@@ -0,0 +1,189 @@
+package hardware
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// hybridCPU monta um sysfs/procfs de um processador híbrido com 2 núcleos P
+// com SMT (cpu0-3) e 4 núcleos E (cpu4-7) que dividem um único L2.
+func hybridCPU(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+
+	var cpuinfo strings.Builder
+	for cpu := 0; cpu < 8; cpu++ {
+		fmt.Fprintf(&cpuinfo, "processor\t: %d\nvendor_id\t: GenuineIntel\nmodel name\t: 12th Gen Intel(R) Core(TM) i5-1235U\n", cpu)
+		fmt.Fprintf(&cpuinfo, "physical id\t: 0\ncore id\t\t: %d\ncpu MHz\t\t: 1200.000\nflags\t\t: fpu vme sse2 ht vmx avx2\n\n", cpu/2)
+	}
+	writeSysfs(t, filepath.Join(root, "proc"), map[string]string{"cpuinfo": cpuinfo.String()})
+
+	cpuDir := filepath.Join(root, "sys/devices/system/cpu")
+	writeSysfs(t, cpuDir, map[string]string{"online": "0-7"})
+	writeSysfs(t, filepath.Join(root, "sys/devices/cpu_core"), map[string]string{"cpus": "0-3"})
+	writeSysfs(t, filepath.Join(root, "sys/devices/cpu_atom"), map[string]string{"cpus": "4-7"})
+
+	for cpu := 0; cpu < 8; cpu++ {
+		dir := filepath.Join(cpuDir, fmt.Sprintf("cpu%d", cpu))
+		siblings := fmt.Sprintf("%d-%d", cpu/2*2, cpu/2*2+1)
+		maxFreq := "4400000"
+		caches := []map[string]string{
+			{"level": "1", "type": "Data", "size": "48K", "shared_cpu_list": siblings},
+			{"level": "1", "type": "Instruction", "size": "32K", "shared_cpu_list": siblings},
+			{"level": "2", "type": "Unified", "size": "1280K", "shared_cpu_list": siblings},
+		}
+		if cpu >= 4 {
+			siblings = fmt.Sprint(cpu)
+			maxFreq = "3300000"
+			caches = []map[string]string{
+				{"level": "1", "type": "Data", "size": "32K", "shared_cpu_list": siblings},
+				{"level": "1", "type": "Instruction", "size": "64K", "shared_cpu_list": siblings},
+				{"level": "2", "type": "Unified", "size": "2048K", "shared_cpu_list": "4-7"},
+			}
+		}
+		caches = append(caches, map[string]string{"level": "3", "type": "Unified", "size": "12288K", "shared_cpu_list": "0-7"})
+
+		writeSysfs(t, filepath.Join(dir, "topology"), map[string]string{
+			"physical_package_id": "0",
+			"core_cpus_list":      siblings,
+		})
+		writeSysfs(t, filepath.Join(dir, "cpufreq"), map[string]string{
+			"cpuinfo_min_freq": "400000",
+			"cpuinfo_max_freq": maxFreq,
+			"scaling_cur_freq": "1200000",
+		})
+		for i, cache := range caches {
+			writeSysfs(t, filepath.Join(dir, fmt.Sprintf("cache/index%d", i)), cache)
+		}
+	}
+
+	writeSysfs(t, filepath.Join(root, "sys/devices/system/node/node0"), map[string]string{
+		"cpulist": "0-7",
+		"meminfo": "Node 0 MemTotal:       16314552 kB\nNode 0 MemFree:         8123456 kB",
+	})
+
+	return root
+}
+
+func TestReadCPUTopologyHybrid(t *testing.T) {
+	topo, err := readCPUTopology(hybridCPU(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if topo.Sockets != 1 || topo.PhysicalCores != 6 || topo.LogicalThreads != 8 {
+		t.Errorf("esperado 1 socket, 6 núcleos e 8 threads, obtido %d, %d e %d", topo.Sockets, topo.PhysicalCores, topo.LogicalThreads)
+	}
+	if topo.PerformanceCores != 2 || topo.EfficiencyCores != 4 {
+		t.Errorf("esperado 2 núcleos P e 4 E, obtido %d e %d", topo.PerformanceCores, topo.EfficiencyCores)
+	}
+
+	if len(topo.Frequencies) != 8 {
+		t.Fatalf("esperado 8 frequências, obtido %d", len(topo.Frequencies))
+	}
+	if f := topo.Frequencies[0]; f.CoreType != "performance" || f.MinMHz != 400 || f.MaxMHz != 4400 || f.CurMHz != 1200 {
+		t.Errorf("frequência inesperada para cpu0: %+v", f)
+	}
+	if f := topo.Frequencies[5]; f.CoreType != "efficiency" || f.MaxMHz != 3300 {
+		t.Errorf("frequência inesperada para cpu5: %+v", f)
+	}
+
+	want := []CPUCache{
+		{Level: 1, Type: "Data", SizeBytes: 32 << 10, Instances: 4},
+		{Level: 1, Type: "Data", SizeBytes: 48 << 10, Instances: 2},
+		{Level: 1, Type: "Instruction", SizeBytes: 32 << 10, Instances: 2},
+		{Level: 1, Type: "Instruction", SizeBytes: 64 << 10, Instances: 4},
+		{Level: 2, Type: "Unified", SizeBytes: 1280 << 10, Instances: 2},
+		{Level: 2, Type: "Unified", SizeBytes: 2048 << 10, Instances: 1},
+		{Level: 3, Type: "Unified", SizeBytes: 12288 << 10, Instances: 1},
+	}
+	if !reflect.DeepEqual(topo.Caches, want) {
+		t.Errorf("caches inesperados:\n obtido  %+v\n esperado %+v", topo.Caches, want)
+	}
+
+	wantNodes := []NUMANode{{ID: 0, CPUs: []int{0, 1, 2, 3, 4, 5, 6, 7}, MemoryBytes: 16314552 * 1024}}
+	if !reflect.DeepEqual(topo.NUMANodes, wantNodes) {
+		t.Errorf("nós NUMA inesperados: %+v", topo.NUMANodes)
+	}
+
+	if got := virtualizationSupport(topo.Flags); got != "VT-x" {
+		t.Errorf("esperado VT-x, obtido %q", got)
+	}
+}
+
+func TestReadCPUTopologyWithoutSysfs(t *testing.T) {
+	root := t.TempDir()
+	var cpuinfo strings.Builder
+	for cpu := 0; cpu < 4; cpu++ {
+		fmt.Fprintf(&cpuinfo, "processor\t: %d\nphysical id\t: %d\ncore id\t\t: 0\ncpu MHz\t\t: 2400.5\nflags\t\t: fpu svm\n\n", cpu, cpu/2)
+	}
+	writeSysfs(t, filepath.Join(root, "proc"), map[string]string{"cpuinfo": cpuinfo.String()})
+
+	topo, err := readCPUTopology(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topo.Sockets != 2 || topo.PhysicalCores != 2 || topo.LogicalThreads != 4 {
+		t.Errorf("esperado 2 sockets, 2 núcleos e 4 threads, obtido %d, %d e %d", topo.Sockets, topo.PhysicalCores, topo.LogicalThreads)
+	}
+	if len(topo.Frequencies) != 4 || topo.Frequencies[3].CurMHz != 2400.5 {
+		t.Errorf("frequências inesperadas: %+v", topo.Frequencies)
+	}
+	if got := virtualizationSupport(topo.Flags); got != "AMD-V" {
+		t.Errorf("esperado AMD-V, obtido %q", got)
+	}
+}
+
+func TestReadCPUTopologyEmpty(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, filepath.Join(root, "proc"), map[string]string{"cpuinfo": ""})
+
+	if _, err := readCPUTopology(root); err == nil {
+		t.Error("esperado erro sem processadores")
+	}
+}
+
+func TestParseCPUInfo(t *testing.T) {
+	// Formato do arm64: blocos por processador seguidos de um bloco global.
+	content := `processor	: 0
+BogoMIPS	: 108.00
+Features	: fp asimd evtstrm aes crc32
+CPU implementer	: 0x41
+CPU part	: 0xd0b
+
+processor	: 1
+BogoMIPS	: 108.00
+Features	: fp asimd evtstrm aes crc32
+CPU implementer	: 0x41
+CPU part	: 0xd0b
+
+Hardware	: BCM2835
+Model	: Raspberry Pi 5 Model B Rev 1.0: test
+`
+	path := filepath.Join(t.TempDir(), "cpuinfo")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	processors, err := parseCPUInfo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processors) != 2 {
+		t.Fatalf("esperado 2 processadores, obtido %d", len(processors))
+	}
+	if processors[1]["processor"] != "1" || processors[1]["CPU part"] != "0xd0b" {
+		t.Errorf("processador inesperado: %v", processors[1])
+	}
+	if processors[0]["Features"] != "fp asimd evtstrm aes crc32" {
+		t.Errorf("Features inesperado: %q", processors[0]["Features"])
+	}
+
+	if _, err := parseCPUInfo(filepath.Join(t.TempDir(), "inexistente")); err == nil {
+		t.Error("esperado erro para arquivo inexistente")
+	}
+}
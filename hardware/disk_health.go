@@ -0,0 +1,357 @@
+package hardware
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/jaypipes/ghw"
+)
+
+var nvmeController = regexp.MustCompile(`^nvme\d+`)
+
+type PhysicalDisk struct {
+	Name       string      `json:"name"`
+	Model      string      `json:"model"`
+	Serial     string      `json:"serial_number"`
+	Firmware   string      `json:"firmware"`
+	Type       string      `json:"type"`
+	Rotational bool        `json:"rotational"`
+	SizeBytes  uint64      `json:"size_bytes"`
+	Bus        string      `json:"bus"`
+	Health     *DiskHealth `json:"health,omitempty"`
+}
+
+type DiskHealth struct {
+	Source string `json:"source"`
+	// Passed fica nil quando o disco não informa o resultado do autoteste
+	// (comum atrás de pontes USB), o que é diferente de reprovado.
+	Passed       *bool            `json:"passed"`
+	Temperature  float64          `json:"temperature_celsius"`
+	PowerOnHours uint64           `json:"power_on_hours"`
+	PowerCycles  uint64           `json:"power_cycles"`
+	Attributes   []SMARTAttribute `json:"smart_attributes,omitempty"`
+	NVMe         *NVMeHealth      `json:"nvme,omitempty"`
+}
+
+type SMARTAttribute struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Value      int    `json:"value"`
+	Worst      int    `json:"worst"`
+	Threshold  int    `json:"threshold"`
+	Raw        uint64 `json:"raw"`
+	WhenFailed string `json:"when_failed,omitempty"`
+}
+
+type NVMeHealth struct {
+	CriticalWarning         uint8  `json:"critical_warning"`
+	AvailableSpare          uint8  `json:"available_spare_percent"`
+	AvailableSpareThreshold uint8  `json:"available_spare_threshold_percent"`
+	PercentageUsed          uint8  `json:"percentage_used"`
+	DataUnitsRead           uint64 `json:"data_units_read"`
+	DataUnitsWritten        uint64 `json:"data_units_written"`
+	MediaErrors             uint64 `json:"media_errors"`
+	UnsafeShutdowns         uint64 `json:"unsafe_shutdowns"`
+	ErrorLogEntries         uint64 `json:"error_log_entries"`
+}
+
+func getPhysicalDisks() ([]PhysicalDisk, error) {
+	if runtime.GOOS != "linux" {
+		return getPhysicalDisksFallback()
+	}
+
+	disks, err := readPhysicalDisks(defaultRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range disks {
+		health, err := readDiskHealth(&disks[i])
+		if err != nil {
+			continue
+		}
+		disks[i].Health = health
+	}
+
+	return disks, nil
+}
+
+func readPhysicalDisks(root string) ([]PhysicalDisk, error) {
+	blocks, err := os.ReadDir(filepath.Join(root, "sys/block"))
+	if err != nil {
+		return nil, err
+	}
+
+	var disks []PhysicalDisk
+	for _, block := range blocks {
+		name := block.Name()
+		dir := filepath.Join(root, "sys/block", name)
+
+		// Dispositivos virtuais (loop, ram, zram, dm-*, md*) não têm "device".
+		if _, err := os.Stat(filepath.Join(dir, "device")); err != nil {
+			continue
+		}
+
+		disk := PhysicalDisk{
+			Name:     name,
+			Model:    readString(filepath.Join(dir, "device/model")),
+			Serial:   readString(filepath.Join(dir, "device/serial")),
+			Firmware: firstNonEmpty(readString(filepath.Join(dir, "device/firmware_rev")), readString(filepath.Join(dir, "device/rev"))),
+		}
+		if sectors, ok := readUint(filepath.Join(dir, "size")); ok {
+			disk.SizeBytes = sectors * 512
+		}
+		rotational, _ := readUint(filepath.Join(dir, "queue/rotational"))
+		disk.Rotational = rotational == 1
+
+		udev := readUdevProperties(root, readString(filepath.Join(dir, "dev")))
+		disk.Serial = firstNonEmpty(disk.Serial, udev["ID_SERIAL_SHORT"])
+		disk.Firmware = firstNonEmpty(disk.Firmware, udev["ID_REVISION"])
+
+		devicePath, _ := filepath.EvalSymlinks(filepath.Join(dir, "device"))
+		switch {
+		case strings.HasPrefix(name, "nvme"):
+			disk.Bus = "nvme"
+		case udev["ID_BUS"] != "":
+			disk.Bus = udev["ID_BUS"]
+		case strings.Contains(devicePath, "/usb"):
+			disk.Bus = "usb"
+		case strings.Contains(devicePath, "/virtio"):
+			disk.Bus = "virtio"
+		case strings.Contains(devicePath, "/ata"):
+			disk.Bus = "ata"
+		default:
+			disk.Bus = "scsi"
+		}
+
+		switch {
+		case disk.Bus == "nvme":
+			disk.Type = "NVMe"
+		case disk.Rotational:
+			disk.Type = "HDD"
+		default:
+			disk.Type = "SSD"
+		}
+
+		disks = append(disks, disk)
+	}
+
+	sort.Slice(disks, func(i, j int) bool { return disks[i].Name < disks[j].Name })
+	return disks, nil
+}
+
+// readUdevProperties lê o banco do udev (/run/udev/data/b<major>:<minor>),
+// onde ficam o serial e o barramento de discos SATA, que o sysfs não expõe.
+func readUdevProperties(root, devNumber string) map[string]string {
+	props := make(map[string]string)
+	if devNumber == "" {
+		return props
+	}
+
+	for _, line := range strings.Split(readString(filepath.Join(root, "run/udev/data", "b"+devNumber)), "\n") {
+		if !strings.HasPrefix(line, "E:") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line[2:], "="); ok {
+			props[key] = value
+		}
+	}
+	return props
+}
+
+func readDiskHealth(disk *PhysicalDisk) (*DiskHealth, error) {
+	device := "/dev/" + disk.Name
+
+	// O smartctl usa o código de saída como máscara de bits e continua
+	// gerando JSON válido quando o disco reporta problemas.
+	out, err := exec.Command("smartctl", "-j", "-a", device).Output()
+	if len(out) > 0 {
+		report, parseErr := parseSmartctlJSON(out)
+		if parseErr == nil {
+			disk.Model = firstNonEmpty(disk.Model, report.Model)
+			disk.Serial = firstNonEmpty(disk.Serial, report.Serial)
+			disk.Firmware = firstNonEmpty(disk.Firmware, report.Firmware)
+			return report.Health, nil
+		}
+		err = parseErr
+	}
+
+	if disk.Bus == "nvme" {
+		controller := "/dev/" + nvmeController.FindString(disk.Name)
+		raw, nvmeErr := exec.Command("nvme", "smart-log", controller, "-b").Output()
+		if nvmeErr == nil {
+			return parseNVMeSmartLog(raw)
+		}
+	}
+
+	return nil, err
+}
+
+type smartctlReport struct {
+	Model    string
+	Serial   string
+	Firmware string
+	Health   *DiskHealth
+}
+
+func parseSmartctlJSON(data []byte) (smartctlReport, error) {
+	var raw struct {
+		ModelName       string `json:"model_name"`
+		SerialNumber    string `json:"serial_number"`
+		FirmwareVersion string `json:"firmware_version"`
+		SmartStatus     *struct {
+			Passed bool `json:"passed"`
+		} `json:"smart_status"`
+		Temperature struct {
+			Current float64 `json:"current"`
+		} `json:"temperature"`
+		PowerOnTime struct {
+			Hours uint64 `json:"hours"`
+		} `json:"power_on_time"`
+		PowerCycleCount    uint64 `json:"power_cycle_count"`
+		ATASmartAttributes struct {
+			Table []struct {
+				ID         int    `json:"id"`
+				Name       string `json:"name"`
+				Value      int    `json:"value"`
+				Worst      int    `json:"worst"`
+				Thresh     int    `json:"thresh"`
+				WhenFailed string `json:"when_failed"`
+				Raw        struct {
+					Value uint64 `json:"value"`
+				} `json:"raw"`
+			} `json:"table"`
+		} `json:"ata_smart_attributes"`
+		NVMeLog *struct {
+			CriticalWarning         uint8  `json:"critical_warning"`
+			AvailableSpare          uint8  `json:"available_spare"`
+			AvailableSpareThreshold uint8  `json:"available_spare_threshold"`
+			PercentageUsed          uint8  `json:"percentage_used"`
+			DataUnitsRead           uint64 `json:"data_units_read"`
+			DataUnitsWritten        uint64 `json:"data_units_written"`
+			MediaErrors             uint64 `json:"media_errors"`
+			UnsafeShutdowns         uint64 `json:"unsafe_shutdowns"`
+			NumErrLogEntries        uint64 `json:"num_err_log_entries"`
+		} `json:"nvme_smart_health_information_log"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return smartctlReport{}, fmt.Errorf("erro ao interpretar saída do smartctl: %v", err)
+	}
+	if raw.SmartStatus == nil && raw.NVMeLog == nil && len(raw.ATASmartAttributes.Table) == 0 {
+		return smartctlReport{}, fmt.Errorf("smartctl não retornou dados SMART")
+	}
+
+	health := &DiskHealth{
+		Source:       "smartctl",
+		Temperature:  raw.Temperature.Current,
+		PowerOnHours: raw.PowerOnTime.Hours,
+		PowerCycles:  raw.PowerCycleCount,
+	}
+	if raw.SmartStatus != nil {
+		health.Passed = &raw.SmartStatus.Passed
+	}
+
+	for _, a := range raw.ATASmartAttributes.Table {
+		health.Attributes = append(health.Attributes, SMARTAttribute{
+			ID:         a.ID,
+			Name:       a.Name,
+			Value:      a.Value,
+			Worst:      a.Worst,
+			Threshold:  a.Thresh,
+			Raw:        a.Raw.Value,
+			WhenFailed: a.WhenFailed,
+		})
+	}
+
+	if raw.NVMeLog != nil {
+		health.NVMe = &NVMeHealth{
+			CriticalWarning:         raw.NVMeLog.CriticalWarning,
+			AvailableSpare:          raw.NVMeLog.AvailableSpare,
+			AvailableSpareThreshold: raw.NVMeLog.AvailableSpareThreshold,
+			PercentageUsed:          raw.NVMeLog.PercentageUsed,
+			DataUnitsRead:           raw.NVMeLog.DataUnitsRead,
+			DataUnitsWritten:        raw.NVMeLog.DataUnitsWritten,
+			MediaErrors:             raw.NVMeLog.MediaErrors,
+			UnsafeShutdowns:         raw.NVMeLog.UnsafeShutdowns,
+			ErrorLogEntries:         raw.NVMeLog.NumErrLogEntries,
+		}
+	}
+
+	return smartctlReport{
+		Model:    raw.ModelName,
+		Serial:   raw.SerialNumber,
+		Firmware: raw.FirmwareVersion,
+		Health:   health,
+	}, nil
+}
+
+// parseNVMeSmartLog interpreta a página de log 02h (SMART / Health
+// Information) de 512 bytes definida na especificação NVMe. Os contadores
+// de 128 bits são truncados nos 64 bits baixos.
+func parseNVMeSmartLog(data []byte) (*DiskHealth, error) {
+	if len(data) < 512 {
+		return nil, fmt.Errorf("log SMART NVMe com tamanho inválido: %d bytes", len(data))
+	}
+
+	u64 := func(offset int) uint64 {
+		return binary.LittleEndian.Uint64(data[offset:])
+	}
+
+	nvme := &NVMeHealth{
+		CriticalWarning:         data[0],
+		AvailableSpare:          data[3],
+		AvailableSpareThreshold: data[4],
+		PercentageUsed:          data[5],
+		DataUnitsRead:           u64(32),
+		DataUnitsWritten:        u64(48),
+		MediaErrors:             u64(160),
+		UnsafeShutdowns:         u64(144),
+		ErrorLogEntries:         u64(176),
+	}
+
+	kelvin := binary.LittleEndian.Uint16(data[1:])
+	passed := nvme.CriticalWarning == 0
+	health := &DiskHealth{
+		Source:       "nvme-log",
+		Passed:       &passed,
+		PowerCycles:  u64(112),
+		PowerOnHours: u64(128),
+		NVMe:         nvme,
+	}
+	if kelvin > 0 {
+		health.Temperature = float64(int(kelvin) - 273)
+	}
+
+	return health, nil
+}
+
+func getPhysicalDisksFallback() ([]PhysicalDisk, error) {
+	block, err := ghw.Block()
+	if err != nil {
+		return nil, err
+	}
+
+	var disks []PhysicalDisk
+	for _, d := range block.Disks {
+		disks = append(disks, PhysicalDisk{
+			Name:       d.Name,
+			Model:      d.Model,
+			Serial:     d.SerialNumber,
+			Type:       d.DriveType.String(),
+			Rotational: d.DriveType == ghw.DRIVE_TYPE_HDD,
+			SizeBytes:  d.SizeBytes,
+			Bus:        strings.ToLower(d.StorageController.String()),
+		})
+	}
+
+	return disks, nil
+}
@@ -0,0 +1,148 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseSmartctlJSONATA(t *testing.T) {
+	report, err := parseSmartctlJSON(readFixture(t, "smartctl_ata.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Model != "Samsung SSD 860 EVO 500GB" || report.Serial != "S3Z1NB0K123456A" || report.Firmware != "RVT04B6Q" {
+		t.Errorf("identificação inesperada: %+v", report)
+	}
+
+	h := report.Health
+	if h.Source != "smartctl" || h.Passed == nil || !*h.Passed || h.Temperature != 34 || h.PowerOnHours != 21733 || h.PowerCycles != 1187 || h.NVMe != nil {
+		t.Errorf("saúde inesperada: %+v", h)
+	}
+	if len(h.Attributes) != 5 {
+		t.Fatalf("esperado 5 atributos, obtido %d", len(h.Attributes))
+	}
+	want := SMARTAttribute{ID: 194, Name: "Temperature_Celsius", Value: 66, Worst: 49, Raw: 167503724578}
+	if h.Attributes[4] != want {
+		t.Errorf("atributo inesperado: %+v", h.Attributes[4])
+	}
+}
+
+func TestParseSmartctlJSONNVMe(t *testing.T) {
+	report, err := parseSmartctlJSON(readFixture(t, "smartctl_nvme.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := report.Health
+	if h.Passed == nil || !*h.Passed || h.Temperature != 38 || h.PowerOnHours != 6218 || h.PowerCycles != 2271 || len(h.Attributes) != 0 {
+		t.Errorf("saúde inesperada: %+v", h)
+	}
+	want := &NVMeHealth{
+		AvailableSpare:          100,
+		AvailableSpareThreshold: 10,
+		PercentageUsed:          3,
+		DataUnitsRead:           26715232,
+		DataUnitsWritten:        31998544,
+		UnsafeShutdowns:         93,
+		ErrorLogEntries:         4,
+	}
+	if !reflect.DeepEqual(h.NVMe, want) {
+		t.Errorf("log NVMe inesperado:\n obtido  %+v\n esperado %+v", h.NVMe, want)
+	}
+}
+
+func TestParseSmartctlJSONFailing(t *testing.T) {
+	report, err := parseSmartctlJSON(readFixture(t, "smartctl_failing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if passed := report.Health.Passed; passed == nil || *passed {
+		t.Error("esperado disco reprovado no SMART")
+	}
+	var failed []int
+	for _, a := range report.Health.Attributes {
+		if a.WhenFailed != "" {
+			failed = append(failed, a.ID)
+		}
+	}
+	if !reflect.DeepEqual(failed, []int{5}) {
+		t.Errorf("esperado só o atributo 5 com falha, obtido %v", failed)
+	}
+}
+
+func TestParseSmartctlJSONWithoutStatus(t *testing.T) {
+	report, err := parseSmartctlJSON(readFixture(t, "smartctl_usb_bridge.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Health.Passed != nil {
+		t.Errorf("sem smart_status o resultado deveria ser desconhecido, obtido %v", *report.Health.Passed)
+	}
+	if len(report.Health.Attributes) != 3 || report.Health.PowerOnHours != 8812 {
+		t.Errorf("saúde inesperada: %+v", report.Health)
+	}
+}
+
+func TestParseSmartctlJSONInvalid(t *testing.T) {
+	// Pontes USB sem suporte a SMART: o smartctl só devolve a identificação.
+	noSMART := `{"smartctl": {"exit_status": 2}, "device": {"name": "/dev/sdc"}, "model_name": "USB Bridge"}`
+
+	for name, data := range map[string]string{
+		"sem SMART": noSMART,
+		"truncado":  `{"model_name": "Samsung SSD`,
+		"vazio":     "",
+	} {
+		if _, err := parseSmartctlJSON([]byte(data)); err == nil {
+			t.Errorf("%s: esperado erro", name)
+		}
+	}
+}
+
+func TestParseNVMeSmartLog(t *testing.T) {
+	h, err := parseNVMeSmartLog(readFixture(t, "nvme_smart_log.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h.Source != "nvme-log" || h.Passed == nil || *h.Passed || h.Temperature != 38 || h.PowerOnHours != 6218 || h.PowerCycles != 2271 {
+		t.Errorf("saúde inesperada: %+v", h)
+	}
+	want := &NVMeHealth{
+		CriticalWarning:         0x04,
+		AvailableSpare:          100,
+		AvailableSpareThreshold: 10,
+		PercentageUsed:          3,
+		DataUnitsRead:           26715232,
+		DataUnitsWritten:        31998544,
+		MediaErrors:             7,
+		UnsafeShutdowns:         93,
+		ErrorLogEntries:         4,
+	}
+	if !reflect.DeepEqual(h.NVMe, want) {
+		t.Errorf("log NVMe inesperado:\n obtido  %+v\n esperado %+v", h.NVMe, want)
+	}
+}
+
+func TestParseNVMeSmartLogTruncated(t *testing.T) {
+	data := readFixture(t, "nvme_smart_log.bin")
+
+	for _, size := range []int{0, 64, 511} {
+		if _, err := parseNVMeSmartLog(data[:size]); err == nil {
+			t.Errorf("esperado erro para log de %d bytes", size)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package hardware
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// Tipos que não representam armazenamento de verdade e ficam de fora do
+// relatório a menos que FilesystemFilter.IncludePseudo ou IncludeTypes peçam.
+var pseudoFilesystems = map[string]bool{
+	"autofs": true, "binfmt_misc": true, "bpf": true, "cgroup": true,
+	"cgroup2": true, "configfs": true, "debugfs": true, "devpts": true,
+	"devtmpfs": true, "efivarfs": true, "fusectl": true, "hugetlbfs": true,
+	"mqueue": true, "nsfs": true, "overlay": true, "proc": true,
+	"pstore": true, "ramfs": true, "rpc_pipefs": true, "securityfs": true,
+	"squashfs": true, "sysfs": true, "tmpfs": true, "tracefs": true,
+	"fuse.gvfsd-fuse": true, "fuse.portal": true, "nfsd": true,
+}
+
+// FilesystemFilter escolhe os pontos de montagem do relatório. Os padrões de
+// caminho usam a sintaxe de path.Match e valem para a subárvore: "/snap/*"
+// pega /snap/core20/1234 e "/mnt/backup" pega tudo abaixo dele. "**" é
+// aceito como sinônimo de "*". Com IncludePaths, só entra o que casar com
+// algum deles (o filtro de tipos continua valendo).
+type FilesystemFilter struct {
+	IncludePseudo bool
+	IncludeTypes  []string
+	ExcludeTypes  []string
+	IncludePaths  []string
+	ExcludePaths  []string
+}
+
+func (f FilesystemFilter) allows(fstype, mountpoint string) bool {
+	for _, t := range f.ExcludeTypes {
+		if t == fstype {
+			return false
+		}
+	}
+	if matchesAnyPath(f.ExcludePaths, mountpoint) {
+		return false
+	}
+	if len(f.IncludePaths) > 0 && !matchesAnyPath(f.IncludePaths, mountpoint) {
+		return false
+	}
+
+	if !pseudoFilesystems[fstype] || f.IncludePseudo {
+		return true
+	}
+	for _, t := range f.IncludeTypes {
+		if t == fstype {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAnyPath testa o ponto de montagem e cada diretório acima dele, já
+// que o "*" do path.Match não atravessa "/".
+func matchesAnyPath(patterns []string, mountpoint string) bool {
+	for _, pattern := range patterns {
+		pattern = path.Clean(strings.ReplaceAll(pattern, "**", "*"))
+		for dir := path.Clean(mountpoint); ; dir = path.Dir(dir) {
+			if ok, _ := path.Match(pattern, dir); ok {
+				return true
+			}
+			if dir == "/" || dir == "." {
+				break
+			}
+		}
+	}
+	return false
+}
+
+func getDiskInfo(filter FilesystemFilter) ([]DiskInfo, error) {
+	partitions, err := disk.Partitions(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var superOptions map[string][]string
+	var fstab map[string][]string
+	if runtime.GOOS == "linux" {
+		superOptions = readSuperOptions(filepath.Join(defaultRoot, "proc/self/mountinfo"))
+		fstab = readFstab(filepath.Join(defaultRoot, "etc/fstab"))
+	}
+
+	var disks []DiskInfo
+	seen := make(map[string]bool)
+
+	for _, partition := range partitions {
+		if !filter.allows(partition.Fstype, partition.Mountpoint) || seen[partition.Mountpoint] {
+			continue
+		}
+		seen[partition.Mountpoint] = true
+
+		usage, err := disk.Usage(partition.Mountpoint)
+		if err != nil {
+			log.Printf("Erro ao obter uso do disco %s: %v", partition.Device, err)
+			continue
+		}
+
+		info := DiskInfo{
+			Device:        partition.Device,
+			Mountpoint:    partition.Mountpoint,
+			Type:          partition.Fstype,
+			Options:       partition.Opts,
+			Total:         usage.Total,
+			Used:          usage.Used,
+			Free:          usage.Free,
+			UsagePercent:  usage.UsedPercent,
+			InodesTotal:   usage.InodesTotal,
+			InodesUsed:    usage.InodesUsed,
+			InodesFree:    usage.InodesFree,
+			InodesPercent: usage.InodesUsedPercent,
+			ReadOnly:      hasOption(partition.Opts, "ro"),
+		}
+
+		if super, ok := superOptions[partition.Mountpoint]; ok {
+			info.ReadOnly = info.ReadOnly || hasOption(super, "ro")
+			info.RemountedReadOnly = remountedReadOnly(info, super, fstab[partition.Mountpoint])
+		}
+
+		disks = append(disks, info)
+	}
+
+	return disks, nil
+}
+
+// remountedReadOnly detecta sistemas de arquivos que o kernel remontou como
+// somente leitura por erro: montados "ro" com a política errors=remount-ro,
+// quando o fstab pede "rw" ou o ext4 já registrou erros no superbloco.
+func remountedReadOnly(info DiskInfo, superOptions, fstabOptions []string) bool {
+	if !info.ReadOnly || !hasOption(superOptions, "errors=remount-ro") {
+		return false
+	}
+
+	if fstabOptions != nil && !hasOption(fstabOptions, "ro") {
+		return true
+	}
+
+	if strings.HasPrefix(info.Type, "ext") {
+		name := filepath.Base(info.Device)
+		if count, ok := readUint(filepath.Join(defaultRoot, "sys/fs/ext4", name, "errors_count")); ok && count > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readSuperOptions devolve as opções do superbloco (campo depois do " - "
+// em /proc/self/mountinfo), que é onde o kernel marca o "ro" quando remonta
+// o sistema de arquivos por erro.
+func readSuperOptions(mountinfo string) map[string][]string {
+	options := make(map[string][]string)
+
+	file, err := os.Open(mountinfo)
+	if err != nil {
+		return options
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), " - ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fields := strings.Fields(parts[0])
+		super := strings.Fields(parts[1])
+		if len(fields) < 5 || len(super) < 3 {
+			continue
+		}
+		options[unescapeMount(fields[4])] = strings.Split(super[2], ",")
+	}
+
+	return options
+}
+
+func readFstab(fstab string) map[string][]string {
+	options := make(map[string][]string)
+
+	for _, line := range strings.Split(readString(fstab), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		options[unescapeMount(fields[1])] = strings.Split(fields[3], ",")
+	}
+
+	return options
+}
+
+// unescapeMount desfaz o escape octal usado pelo kernel e pelo fstab,
+// como "\040" para espaço.
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			var c byte
+			valid := true
+			for _, d := range s[i+1 : i+4] {
+				if d < '0' || d > '7' {
+					valid = false
+					break
+				}
+				c = c*8 + byte(d-'0')
+			}
+			if valid {
+				b.WriteByte(c)
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func hasOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package hardware
+
+import "testing"
+
+func TestFilesystemFilterPaths(t *testing.T) {
+	filter := FilesystemFilter{ExcludePaths: []string{"/snap/*", "/var/lib/docker/*", "/mnt/backup", "/media/**"}}
+
+	tests := []struct {
+		fstype     string
+		mountpoint string
+		want       bool
+	}{
+		{"ext4", "/", true},
+		{"squashfs", "/snap/core20/1234", false},
+		{"ext4", "/snap/core20/1234", false},
+		{"overlay", "/var/lib/docker/overlay2/4f2c9e0d7a/merged", false},
+		{"ext4", "/var/lib/docker", true},
+		{"ext4", "/var/lib/dockerd", true},
+		{"ext4", "/mnt/backup", false},
+		{"nfs", "/mnt/backup/2024/", false},
+		{"ext4", "/mnt/backups", true},
+		{"vfat", "/media/user/USB DISK", false},
+		{"tmpfs", "/run/user/1000", false},
+	}
+	for _, tt := range tests {
+		if got := filter.allows(tt.fstype, tt.mountpoint); got != tt.want {
+			t.Errorf("allows(%s, %s) = %v, esperado %v", tt.fstype, tt.mountpoint, got, tt.want)
+		}
+	}
+}
+
+func TestFilesystemFilterIncludePaths(t *testing.T) {
+	filter := FilesystemFilter{
+		IncludeTypes: []string{"tmpfs"},
+		IncludePaths: []string{"/srv", "/data/*", "/dev/shm"},
+		ExcludePaths: []string{"/srv/cache"},
+	}
+
+	tests := []struct {
+		fstype     string
+		mountpoint string
+		want       bool
+	}{
+		{"ext4", "/", false},
+		{"ext4", "/home", false},
+		{"xfs", "/srv", true},
+		{"xfs", "/srv/www", true},
+		{"xfs", "/srv/cache", false},
+		{"ext4", "/data", false},
+		{"ext4", "/data/disk1", true},
+		{"tmpfs", "/dev/shm", true},
+		{"proc", "/srv/proc", false},
+	}
+	for _, tt := range tests {
+		if got := filter.allows(tt.fstype, tt.mountpoint); got != tt.want {
+			t.Errorf("allows(%s, %s) = %v, esperado %v", tt.fstype, tt.mountpoint, got, tt.want)
+		}
+	}
+}
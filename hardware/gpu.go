@@ -0,0 +1,271 @@
+package hardware
+
+import (
+	"bufio"
+	"math"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jaypipes/ghw"
+
+	"monitoramento/sampler"
+)
+
+var (
+	drmCard  = regexp.MustCompile(`^card\d+$`)
+	dpmLevel = regexp.MustCompile(`(\d+)\s*[Mm][Hh]z\s*\*`)
+
+	gpuVendors = map[string]string{
+		"0x1002": "AMD",
+		"0x8086": "Intel",
+		"0x10de": "NVIDIA",
+	}
+)
+
+const nvidiaSMIQuery = "pci.bus_id,name,memory.total,memory.used,utilization.gpu,temperature.gpu,power.draw,clocks.gr,clocks.mem"
+
+func getGPUInfo() ([]GPUInfo, error) {
+	gpus := readDRMGPUs(defaultRoot)
+
+	gpu, err := ghw.GPU()
+	if err == nil {
+		for _, card := range gpu.GraphicsCards {
+			if card.DeviceInfo == nil {
+				continue
+			}
+			g := findGPU(&gpus, card.Address)
+			g.Model = card.DeviceInfo.Product.Name
+			if g.Vendor == "" {
+				g.Vendor = card.DeviceInfo.Vendor.Name
+			}
+		}
+	}
+
+	if out, smiErr := exec.Command("nvidia-smi", "--query-gpu="+nvidiaSMIQuery, "--format=csv,noheader,nounits").Output(); smiErr == nil {
+		for _, n := range parseNvidiaSMI(string(out)) {
+			g := findGPU(&gpus, n.PCIAddress)
+			address, driver := g.PCIAddress, g.Driver
+			*g = n
+			g.PCIAddress = address
+			if driver != "" {
+				g.Driver = driver
+			}
+		}
+	}
+
+	if len(gpus) == 0 && err != nil {
+		return nil, err
+	}
+
+	return gpus, nil
+}
+
+func findGPU(gpus *[]GPUInfo, address string) *GPUInfo {
+	for i := range *gpus {
+		if (*gpus)[i].PCIAddress == address {
+			return &(*gpus)[i]
+		}
+	}
+	*gpus = append(*gpus, GPUInfo{PCIAddress: address})
+	return &(*gpus)[len(*gpus)-1]
+}
+
+func readDRMGPUs(root string) []GPUInfo {
+	cards, _ := filepath.Glob(filepath.Join(root, "sys/class/drm/card*"))
+
+	var gpus []GPUInfo
+	idle := make(map[int]string)
+	for _, card := range cards {
+		if !drmCard.MatchString(filepath.Base(card)) {
+			continue
+		}
+
+		device := filepath.Join(card, "device")
+		g := GPUInfo{
+			PCIAddress: linkBase(device),
+			Driver:     linkBase(filepath.Join(device, "driver")),
+			Vendor:     gpuVendors[readString(filepath.Join(device, "vendor"))],
+		}
+		if g.PCIAddress == "" {
+			g.PCIAddress = filepath.Base(card)
+		}
+
+		switch g.Driver {
+		case "amdgpu":
+			readAMDGPU(device, &g)
+		case "i915", "xe":
+			if path := readIntelGPU(card, device, &g); path != "" {
+				idle[len(gpus)] = path
+			}
+		}
+		readGPUHwmon(device, &g)
+
+		gpus = append(gpus, g)
+	}
+
+	if len(idle) > 0 {
+		intelGPUUsage(gpus, idle)
+	}
+
+	return gpus
+}
+
+func readAMDGPU(device string, g *GPUInfo) {
+	g.Memory, _ = readUint(filepath.Join(device, "mem_info_vram_total"))
+	g.MemoryUsed, _ = readUint(filepath.Join(device, "mem_info_vram_used"))
+	if busy, ok := readUint(filepath.Join(device, "gpu_busy_percent")); ok {
+		g.Usage = float64(busy)
+	}
+	g.ClockMHz = currentDPMLevel(readString(filepath.Join(device, "pp_dpm_sclk")))
+	g.MemoryClockMHz = currentDPMLevel(readString(filepath.Join(device, "pp_dpm_mclk")))
+}
+
+// readIntelGPU devolve o arquivo com o tempo acumulado em RC6 (ociosa), do
+// qual sai o uso da GPU.
+func readIntelGPU(card, device string, g *GPUInfo) string {
+	if mhz, ok := readUint(filepath.Join(card, "gt_act_freq_mhz")); ok {
+		g.ClockMHz = float64(mhz)
+	} else if mhz, ok := readUint(filepath.Join(card, "gt_cur_freq_mhz")); ok {
+		g.ClockMHz = float64(mhz)
+	} else if mhz, ok := readUint(filepath.Join(device, "tile0/gt0/freq0/act_freq")); ok {
+		g.ClockMHz = float64(mhz)
+	}
+
+	// Placas dedicadas (Arc) expõem a memória local como uma região de VRAM.
+	if total, ok := readUint(filepath.Join(device, "lmem_total_bytes")); ok {
+		g.Memory = total
+	}
+
+	for _, path := range []string{
+		filepath.Join(card, "gt/gt0/rc6_residency_ms"),
+		filepath.Join(card, "power/rc6_residency_ms"),
+		filepath.Join(device, "tile0/gt0/gtidle/idle_residency_ms"),
+	} {
+		if _, ok := readUint(path); ok {
+			return path
+		}
+	}
+	return ""
+}
+
+var intelSampler sampler.Sampler
+
+// Prime faz a primeira leitura dos contadores usados pela coleta de
+// hardware; ver sampler.Warm.
+func Prime() {
+	readDRMGPUs(defaultRoot)
+}
+
+// intelGPUUsage estima o uso das GPUs Intel pelo tempo que não passaram em
+// RC6 desde o ciclo anterior; o i915 e o xe não têm um equivalente ao
+// gpu_busy_percent do amdgpu.
+func intelGPUUsage(gpus []GPUInfo, idle map[int]string) {
+	interval, err := intelSampler.Sample(func() (sampler.Snapshot, error) {
+		snapshot := make(sampler.Snapshot)
+		for i, path := range idle {
+			if ms, ok := readUint(path); ok {
+				snapshot[gpus[i].PCIAddress] = ms
+			}
+		}
+		return snapshot, nil
+	})
+	if err != nil {
+		return
+	}
+
+	elapsed := interval.Elapsed.Seconds() * 1000
+	if elapsed <= 0 {
+		return
+	}
+	for i := range idle {
+		if ms, ok := interval.Delta(gpus[i].PCIAddress); ok {
+			gpus[i].Usage = math.Max(0, math.Min(100, 100-float64(ms)/elapsed*100))
+		}
+	}
+}
+
+func readGPUHwmon(device string, g *GPUInfo) {
+	hwmons, _ := filepath.Glob(filepath.Join(device, "hwmon/hwmon*"))
+	for _, hwmon := range hwmons {
+		if temp, ok := readInt(filepath.Join(hwmon, "temp1_input")); ok && g.Temperature == 0 {
+			g.Temperature = float64(temp) / 1000
+		}
+		// Potência em microwatts; amdgpu usa power1_average em placas mais
+		// antigas e power1_input nas mais novas.
+		for _, name := range []string{"power1_average", "power1_input"} {
+			if power, ok := readUint(filepath.Join(hwmon, name)); ok && g.PowerWatts == 0 {
+				g.PowerWatts = float64(power) / 1e6
+			}
+		}
+		if g.ClockMHz == 0 {
+			if hz, ok := readUint(filepath.Join(hwmon, "freq1_input")); ok {
+				g.ClockMHz = float64(hz) / 1e6
+			}
+		}
+	}
+}
+
+// currentDPMLevel extrai o nível ativo (marcado com *) de arquivos como
+// pp_dpm_sclk: "0: 500Mhz\n1: 1340Mhz *".
+func currentDPMLevel(levels string) float64 {
+	match := dpmLevel.FindStringSubmatch(levels)
+	if match == nil {
+		return 0
+	}
+	mhz, _ := strconv.ParseFloat(match[1], 64)
+	return mhz
+}
+
+func parseNvidiaSMI(output string) []GPUInfo {
+	var gpus []GPUInfo
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ",")
+		if len(fields) < 9 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		gpus = append(gpus, GPUInfo{
+			Vendor:         "NVIDIA",
+			Driver:         "nvidia",
+			PCIAddress:     normalizePCIAddress(fields[0]),
+			Model:          fields[1],
+			Memory:         uint64(smiFloat(fields[2]) * 1024 * 1024),
+			MemoryUsed:     uint64(smiFloat(fields[3]) * 1024 * 1024),
+			Usage:          smiFloat(fields[4]),
+			Temperature:    smiFloat(fields[5]),
+			PowerWatts:     smiFloat(fields[6]),
+			ClockMHz:       smiFloat(fields[7]),
+			MemoryClockMHz: smiFloat(fields[8]),
+		})
+	}
+
+	return gpus
+}
+
+// smiFloat trata "[N/A]" e "[Not Supported]" como zero.
+func smiFloat(value string) float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// normalizePCIAddress converte "00000000:01:00.0" (nvidia-smi) para o
+// formato do sysfs, "0000:01:00.0".
+func normalizePCIAddress(address string) string {
+	address = strings.ToLower(address)
+	parts := strings.SplitN(address, ":", 2)
+	if len(parts) == 2 && len(parts[0]) > 4 {
+		address = parts[0][len(parts[0])-4:] + ":" + parts[1]
+	}
+	return address
+}
@@ -0,0 +1,129 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// drmCardFixture cria sys/class/drm/<card> apontando para um dispositivo PCI
+// ligado ao driver informado, como no sysfs real.
+func drmCardFixture(t *testing.T, root, card, address, driver string, files map[string]string) string {
+	t.Helper()
+	device := filepath.Join(root, "sys/devices/pci0000:00", address)
+	writeSysfs(t, device, files)
+
+	drivers := filepath.Join(root, "sys/bus/pci/drivers", driver)
+	if err := os.MkdirAll(drivers, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(drivers, filepath.Join(device, "driver")); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(root, "sys/class/drm", card)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(device, filepath.Join(dir, "device")); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestReadDRMGPUs(t *testing.T) {
+	root := t.TempDir()
+
+	amd := drmCardFixture(t, root, "card0", "0000:03:00.0", "amdgpu", map[string]string{
+		"vendor":              "0x1002",
+		"mem_info_vram_total": "8573157376",
+		"mem_info_vram_used":  "1048576000",
+		"gpu_busy_percent":    "37",
+		"pp_dpm_sclk":         "0: 500Mhz\n1: 1340Mhz *\n2: 2100Mhz",
+		"pp_dpm_mclk":         "0: 96Mhz\n1: 1000Mhz *",
+	})
+	writeSysfs(t, filepath.Join(amd, "device/hwmon/hwmon3"), map[string]string{
+		"temp1_input":    "54000",
+		"power1_average": "23000000",
+	})
+	// Conectores aparecem como card0-DP-1 e não são placas.
+	writeSysfs(t, filepath.Join(root, "sys/class/drm/card0-DP-1"), map[string]string{"status": "connected"})
+
+	intel := drmCardFixture(t, root, "card1", "0000:00:02.0", "i915", map[string]string{"vendor": "0x8086"})
+	writeSysfs(t, intel, map[string]string{"gt_act_freq_mhz": "350", "gt_cur_freq_mhz": "700"})
+	writeSysfs(t, filepath.Join(intel, "gt/gt0"), map[string]string{"rc6_residency_ms": "1000"})
+
+	gpus := readDRMGPUs(root)
+	if len(gpus) != 2 {
+		t.Fatalf("esperado 2 GPUs, obtido %d: %+v", len(gpus), gpus)
+	}
+
+	want := GPUInfo{
+		Vendor:         "AMD",
+		Driver:         "amdgpu",
+		PCIAddress:     "0000:03:00.0",
+		Memory:         8573157376,
+		MemoryUsed:     1048576000,
+		Temperature:    54,
+		Usage:          37,
+		PowerWatts:     23,
+		ClockMHz:       1340,
+		MemoryClockMHz: 1000,
+	}
+	if gpus[0] != want {
+		t.Errorf("amdgpu inesperada:\n obtido  %+v\n esperado %+v", gpus[0], want)
+	}
+
+	// O rc6_residency_ms não andou durante a primeira amostra: a GPU não
+	// ficou ociosa em nenhum momento.
+	if g := gpus[1]; g.Vendor != "Intel" || g.Driver != "i915" || g.PCIAddress != "0000:00:02.0" || g.ClockMHz != 350 || g.Usage != 100 {
+		t.Errorf("i915 inesperada: %+v", g)
+	}
+
+	// Mais tempo em RC6 do que o decorrido fica limitado a 0% de uso.
+	writeSysfs(t, filepath.Join(intel, "gt/gt0"), map[string]string{"rc6_residency_ms": "3600000"})
+	if gpus = readDRMGPUs(root); gpus[1].Usage != 0 {
+		t.Errorf("esperado uso 0 para i915 ociosa, obtido %v", gpus[1].Usage)
+	}
+}
+
+func TestParseNvidiaSMI(t *testing.T) {
+	output := "00000000:01:00.0, NVIDIA GeForce RTX 3060, 12288, 1024, 15, 45, 32.50, 1320, 7500\n" +
+		"00000000:41:00.0, NVIDIA A100-SXM4-40GB, 40960, [N/A], [N/A], 38, [Not Supported], [N/A], 1215\n" +
+		"linha inválida\n"
+
+	gpus := parseNvidiaSMI(output)
+	if len(gpus) != 2 {
+		t.Fatalf("esperado 2 GPUs, obtido %d", len(gpus))
+	}
+
+	want := GPUInfo{
+		Vendor:         "NVIDIA",
+		Driver:         "nvidia",
+		PCIAddress:     "0000:01:00.0",
+		Model:          "NVIDIA GeForce RTX 3060",
+		Memory:         12288 << 20,
+		MemoryUsed:     1024 << 20,
+		Usage:          15,
+		Temperature:    45,
+		PowerWatts:     32.5,
+		ClockMHz:       1320,
+		MemoryClockMHz: 7500,
+	}
+	if gpus[0] != want {
+		t.Errorf("GPU inesperada:\n obtido  %+v\n esperado %+v", gpus[0], want)
+	}
+
+	want = GPUInfo{
+		Vendor:         "NVIDIA",
+		Driver:         "nvidia",
+		PCIAddress:     "0000:41:00.0",
+		Model:          "NVIDIA A100-SXM4-40GB",
+		Memory:         40960 << 20,
+		Temperature:    38,
+		MemoryClockMHz: 1215,
+	}
+	if gpus[1] != want {
+		t.Errorf("campos [N/A] deveriam ficar zerados:\n obtido  %+v\n esperado %+v", gpus[1], want)
+	}
+}
@@ -3,54 +3,101 @@ package hardware
 import (
 	"fmt"
 	"log"
-	"time"
+	"runtime"
 
 	"github.com/jaypipes/ghw"
 	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/mem"
 )
 
 type Info struct {
-	CPU           CPUInfo     `json:"cpu"`
-	Memory        MemoryInfo  `json:"memory"`
-	Disk          []DiskInfo  `json:"disk"`
-	GPU           []GPUInfo   `json:"gpu"`
-	Motherboard   Motherboard `json:"motherboard"`
-	BIOS          BIOSInfo    `json:"bios"`
-	USB           []USBDevice `json:"usb_devices"`
+	CPU           CPUInfo        `json:"cpu"`
+	Memory        MemoryInfo     `json:"memory"`
+	Disk          []DiskInfo     `json:"disk"`
+	PhysicalDisks []PhysicalDisk `json:"physical_disks"`
+	GPU           []GPUInfo      `json:"gpu"`
+	Motherboard   Motherboard    `json:"motherboard"`
+	BIOS          BIOSInfo       `json:"bios"`
+	USB           []USBDevice    `json:"usb_devices"`
+	Sensors       SensorInfo     `json:"sensors"`
 }
 
 type CPUInfo struct {
-	Model       string   `json:"model"`
-	Cores       int      `json:"cores"`
-	Threads     int      `json:"threads"`
-	Frequency   float64  `json:"frequency_ghz"`
-	Temperature float64  `json:"temperature_celsius"`
-	Usage       float64  `json:"usage_percent"`
+	Model            string         `json:"model"`
+	Sockets          int            `json:"sockets"`
+	Cores            int            `json:"cores"`
+	Threads          int            `json:"threads"`
+	PerformanceCores int            `json:"performance_cores,omitempty"`
+	EfficiencyCores  int            `json:"efficiency_cores,omitempty"`
+	Frequency        float64        `json:"frequency_ghz"`
+	Frequencies      []CPUFrequency `json:"frequencies"`
+	Caches           []CPUCache     `json:"caches"`
+	NUMANodes        []NUMANode     `json:"numa_nodes"`
+	Flags            []string       `json:"flags"`
+	Virtualization   string         `json:"virtualization"`
+	Hypervisor       bool           `json:"hypervisor"`
+	Temperature      float64        `json:"temperature_celsius"`
+	// Preenchido por quem chama Collect com o uso medido pela coleta de
+	// desempenho do mesmo ciclo.
+	Usage float64 `json:"usage_percent"`
 }
 
 type MemoryInfo struct {
-	Total        uint64  `json:"total_bytes"`
-	Used         uint64  `json:"used_bytes"`
-	Free         uint64  `json:"free_bytes"`
-	UsagePercent float64 `json:"usage_percent"`
+	Total             uint64  `json:"total_bytes"`
+	Used              uint64  `json:"used_bytes"`
+	Free              uint64  `json:"free_bytes"`
+	Available         uint64  `json:"available_bytes"`
+	UsagePercent      float64 `json:"usage_percent"`
+	Buffers           uint64  `json:"buffers_bytes"`
+	Cached            uint64  `json:"cached_bytes"`
+	Slab              uint64  `json:"slab_bytes"`
+	Dirty             uint64  `json:"dirty_bytes"`
+	Writeback         uint64  `json:"writeback_bytes"`
+	SwapTotal         uint64  `json:"swap_total_bytes"`
+	SwapUsed          uint64  `json:"swap_used_bytes"`
+	SwapFree          uint64  `json:"swap_free_bytes"`
+	SwapUsagePercent  float64 `json:"swap_usage_percent"`
+	HugePagesTotal    uint64  `json:"hugepages_total"`
+	HugePagesFree     uint64  `json:"hugepages_free"`
+	HugePagesReserved uint64  `json:"hugepages_reserved"`
+	HugePagesSurplus  uint64  `json:"hugepages_surplus"`
+	HugePageSize      uint64  `json:"hugepage_size_bytes"`
+	DIMMs             []DIMM  `json:"dimms"`
 }
 
 type DiskInfo struct {
-	Device       string  `json:"device"`
-	Type         string  `json:"type"`
-	Total        uint64  `json:"total_bytes"`
-	Used         uint64  `json:"used_bytes"`
-	Free         uint64  `json:"free_bytes"`
-	UsagePercent float64 `json:"usage_percent"`
+	Device            string   `json:"device"`
+	Mountpoint        string   `json:"mountpoint"`
+	Type              string   `json:"type"`
+	Options           []string `json:"mount_options"`
+	Total             uint64   `json:"total_bytes"`
+	Used              uint64   `json:"used_bytes"`
+	Free              uint64   `json:"free_bytes"`
+	UsagePercent      float64  `json:"usage_percent"`
+	InodesTotal       uint64   `json:"inodes_total"`
+	InodesUsed        uint64   `json:"inodes_used"`
+	InodesFree        uint64   `json:"inodes_free"`
+	InodesPercent     float64  `json:"inodes_usage_percent"`
+	ReadOnly          bool     `json:"read_only"`
+	RemountedReadOnly bool     `json:"remounted_read_only"`
+}
+
+type Options struct {
+	Filesystems FilesystemFilter
 }
 
 type GPUInfo struct {
-	Model       string  `json:"model"`
-	Memory      uint64  `json:"memory_bytes"`
-	Temperature float64 `json:"temperature_celsius"`
-	Usage       float64 `json:"usage_percent"`
+	Model          string  `json:"model"`
+	Vendor         string  `json:"vendor"`
+	Driver         string  `json:"driver"`
+	PCIAddress     string  `json:"pci_address"`
+	Memory         uint64  `json:"memory_bytes"`
+	MemoryUsed     uint64  `json:"memory_used_bytes"`
+	Temperature    float64 `json:"temperature_celsius"`
+	Usage          float64 `json:"usage_percent"`
+	PowerWatts     float64 `json:"power_watts"`
+	ClockMHz       float64 `json:"clock_mhz"`
+	MemoryClockMHz float64 `json:"memory_clock_mhz"`
 }
 
 type Motherboard struct {
@@ -66,31 +113,55 @@ type BIOSInfo struct {
 }
 
 type USBDevice struct {
-	Name         string `json:"name"`
-	VendorID     string `json:"vendor_id"`
-	ProductID    string `json:"product_id"`
-	SerialNumber string `json:"serial_number"`
+	Name             string   `json:"name"`
+	Bus              int      `json:"bus"`
+	Device           int      `json:"device"`
+	Path             string   `json:"port_path"`
+	VendorID         string   `json:"vendor_id"`
+	ProductID        string   `json:"product_id"`
+	VendorName       string   `json:"vendor_name"`
+	ProductName      string   `json:"product_name"`
+	Manufacturer     string   `json:"manufacturer"`
+	Product          string   `json:"product"`
+	SerialNumber     string   `json:"serial_number"`
+	Class            string   `json:"class"`
+	ClassName        string   `json:"class_name"`
+	InterfaceClasses []string `json:"interface_classes"`
+	Speed            string   `json:"speed_mbps"`
+	Drivers          []string `json:"drivers"`
+	PolicyVerdict    string   `json:"policy_verdict,omitempty"`
 }
 
-func Collect() Info {
+func Collect(opts Options) Info {
 	var info Info
 	var err error
 
+	info.Sensors, err = ReadSensors()
+	if err != nil {
+		log.Printf("Erro ao coletar sensores: %v", err)
+	}
+
 	info.CPU, err = getCPUInfo()
 	if err != nil {
 		log.Printf("Erro ao coletar informações da CPU: %v", err)
 	}
+	info.CPU.Temperature = info.Sensors.CPUPackage
 
 	info.Memory, err = getMemoryInfo()
 	if err != nil {
 		log.Printf("Erro ao coletar informações da memória: %v", err)
 	}
 
-	info.Disk, err = getDiskInfo()
+	info.Disk, err = getDiskInfo(opts.Filesystems)
 	if err != nil {
 		log.Printf("Erro ao coletar informações do disco: %v", err)
 	}
 
+	info.PhysicalDisks, err = getPhysicalDisks()
+	if err != nil {
+		log.Printf("Erro ao coletar informações dos discos físicos: %v", err)
+	}
+
 	info.GPU, err = getGPUInfo()
 	if err != nil {
 		log.Printf("Erro ao coletar informações da GPU: %v", err)
@@ -124,21 +195,41 @@ func getCPUInfo() (CPUInfo, error) {
 		return CPUInfo{}, fmt.Errorf("nenhuma informação de CPU encontrada")
 	}
 
-	percent, err := cpu.Percent(time.Second, false)
-	if err != nil {
-		log.Printf("Erro ao obter uso da CPU: %v", err)
+	info := CPUInfo{
+		Model:     cpuInfo[0].ModelName,
+		Frequency: cpuInfo[0].Mhz / 1000,
+		Flags:     cpuInfo[0].Flags,
 	}
 
-	temperature := 0.0
+	if runtime.GOOS == "linux" {
+		topo, err := readCPUTopology(defaultRoot)
+		if err != nil {
+			return info, err
+		}
+		info.Sockets = topo.Sockets
+		info.Cores = topo.PhysicalCores
+		info.Threads = topo.LogicalThreads
+		info.PerformanceCores = topo.PerformanceCores
+		info.EfficiencyCores = topo.EfficiencyCores
+		info.Frequencies = topo.Frequencies
+		info.Caches = topo.Caches
+		info.NUMANodes = topo.NUMANodes
+		info.Flags = topo.Flags
+	} else {
+		// Fora do Linux o gopsutil devolve uma entrada por socket.
+		info.Sockets = len(cpuInfo)
+		info.Cores, _ = cpu.Counts(false)
+		info.Threads, _ = cpu.Counts(true)
+	}
 
-	return CPUInfo{
-		Model:       cpuInfo[0].ModelName,
-		Cores:       int(cpuInfo[0].Cores),
-		Threads:     int(cpuInfo[0].Cores * 2),
-		Frequency:   cpuInfo[0].Mhz / 1000,
-		Temperature: temperature,
-		Usage:       percent[0],
-	}, nil
+	info.Virtualization = virtualizationSupport(info.Flags)
+	for _, flag := range info.Flags {
+		if flag == "hypervisor" {
+			info.Hypervisor = true
+		}
+	}
+
+	return info, nil
 }
 
 func getMemoryInfo() (MemoryInfo, error) {
@@ -147,58 +238,65 @@ func getMemoryInfo() (MemoryInfo, error) {
 		return MemoryInfo{}, err
 	}
 
-	return MemoryInfo{
-		Total:        memInfo.Total,
-		Used:         memInfo.Used,
-		Free:         memInfo.Free,
-		UsagePercent: memInfo.UsedPercent,
-	}, nil
-}
+	info := MemoryInfo{
+		Total:             memInfo.Total,
+		Used:              memInfo.Used,
+		Free:              memInfo.Free,
+		Available:         memInfo.Available,
+		UsagePercent:      memInfo.UsedPercent,
+		Buffers:           memInfo.Buffers,
+		Cached:            memInfo.Cached,
+		Slab:              memInfo.Slab,
+		Dirty:             memInfo.Dirty,
+		Writeback:         memInfo.WriteBack,
+		HugePagesTotal:    memInfo.HugePagesTotal,
+		HugePagesFree:     memInfo.HugePagesFree,
+		HugePagesReserved: memInfo.HugePagesRsvd,
+		HugePagesSurplus:  memInfo.HugePagesSurp,
+		HugePageSize:      memInfo.HugePageSize,
+	}
 
-func getDiskInfo() ([]DiskInfo, error) {
-	partitions, err := disk.Partitions(false)
+	swap, err := mem.SwapMemory()
 	if err != nil {
-		return nil, err
+		log.Printf("Erro ao obter informações de swap: %v", err)
+	} else {
+		info.SwapTotal = swap.Total
+		info.SwapUsed = swap.Used
+		info.SwapFree = swap.Free
+		info.SwapUsagePercent = swap.UsedPercent
 	}
 
-	var disks []DiskInfo
-
-	for _, partition := range partitions {
-		usage, err := disk.Usage(partition.Mountpoint)
-		if err != nil {
-			log.Printf("Erro ao obter uso do disco %s: %v", partition.Device, err)
-			continue
-		}
-
-		disks = append(disks, DiskInfo{
-			Device:       partition.Device,
-			Type:         partition.Fstype,
-			Total:        usage.Total,
-			Used:         usage.Used,
-			Free:         usage.Free,
-			UsagePercent: usage.UsedPercent,
-		})
+	info.DIMMs, err = getDIMMs()
+	if err != nil {
+		log.Printf("Erro ao obter os módulos de memória: %v", err)
 	}
 
-	return disks, nil
+	return info, nil
 }
 
-func getGPUInfo() ([]GPUInfo, error) {
-	gpu, err := ghw.GPU()
+func getDIMMs() ([]DIMM, error) {
+	if runtime.GOOS == "linux" {
+		return readDIMMs(defaultRoot)
+	}
+
+	memory, err := ghw.Memory()
 	if err != nil {
 		return nil, err
 	}
 
-	var gpus []GPUInfo
-
-	for _, card := range gpu.GraphicsCards {
-		gpus = append(gpus, GPUInfo{
-			Model:  card.DeviceInfo.Product.Name,
-			Memory: 0,
+	var dimms []DIMM
+	for _, module := range memory.Modules {
+		dimms = append(dimms, DIMM{
+			Slot:         module.Location,
+			Installed:    module.SizeBytes > 0,
+			SizeBytes:    uint64(module.SizeBytes),
+			Manufacturer: module.Vendor,
+			SerialNumber: module.SerialNumber,
+			PartNumber:   module.Label,
 		})
 	}
 
-	return gpus, nil
+	return dimms, nil
 }
 
 func getMotherboardInfo() (Motherboard, error) {
@@ -226,27 +324,3 @@ func getBIOSInfo() (BIOSInfo, error) {
 		ReleaseDate: bios.Date,
 	}, nil
 }
-
-func getUSBInfo() ([]USBDevice, error) {
-	option := ghw.WithChroot("/")
-	block, err := ghw.Block(option)
-	if err != nil {
-		return nil, fmt.Errorf("error getting block info: %v", err)
-	}
-
-	var devices []USBDevice
-
-	for _, disk := range block.Disks {
-		if disk.IsRemovable {
-			devices = append(devices, USBDevice{
-				Name:         disk.Model,
-				VendorID:     disk.Vendor,
-				ProductID:    disk.Model,
-				SerialNumber: disk.SerialNumber,
-			})
-		}
-	}
-
-	return devices, nil
-}
-
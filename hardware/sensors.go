@@ -0,0 +1,277 @@
+package hardware
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+type Sensor struct {
+	Chip     string  `json:"chip"`
+	Device   string  `json:"device,omitempty"`
+	Label    string  `json:"label"`
+	Current  float64 `json:"current"`
+	Min      float64 `json:"min,omitempty"`
+	High     float64 `json:"high,omitempty"`
+	Critical float64 `json:"critical,omitempty"`
+}
+
+type ThermalZone struct {
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Temperature float64 `json:"temperature_celsius"`
+	Critical    float64 `json:"critical_celsius,omitempty"`
+}
+
+type CoreTemperature struct {
+	Label       string  `json:"label"`
+	Temperature float64 `json:"temperature_celsius"`
+}
+
+type SensorInfo struct {
+	CPUPackage   float64           `json:"cpu_package_celsius"`
+	CPUCores     []CoreTemperature `json:"cpu_cores"`
+	GPU          float64           `json:"gpu_celsius"`
+	Disks        []float64         `json:"disk_celsius"`
+	Temperatures []Sensor          `json:"temperatures"`
+	Fans         []Sensor          `json:"fans_rpm"`
+	Voltages     []Sensor          `json:"voltages"`
+	ThermalZones []ThermalZone     `json:"thermal_zones"`
+}
+
+var (
+	cpuChips  = map[string]bool{"coretemp": true, "k10temp": true, "zenpower": true, "cpu_thermal": true}
+	gpuChips  = map[string]bool{"amdgpu": true, "radeon": true, "nouveau": true}
+	diskChips = map[string]bool{"nvme": true, "drivetemp": true}
+
+	sensorFile = regexp.MustCompile(`^(temp|fan|in)(\d+)_input$`)
+)
+
+func ReadSensors() (SensorInfo, error) {
+	if runtime.GOOS != "linux" {
+		return readSensorsFallback()
+	}
+	return readSensors(defaultRoot)
+}
+
+func readSensors(root string) (SensorInfo, error) {
+	var info SensorInfo
+
+	hwmons, _ := filepath.Glob(filepath.Join(root, "sys/class/hwmon/hwmon*"))
+	for _, dir := range hwmons {
+		readHwmon(dir, &info)
+	}
+
+	zones, _ := filepath.Glob(filepath.Join(root, "sys/class/thermal/thermal_zone*"))
+	for _, dir := range zones {
+		if zone, ok := readThermalZone(dir); ok {
+			info.ThermalZones = append(info.ThermalZones, zone)
+		}
+	}
+
+	mapCPUTemperatures(&info)
+
+	if len(hwmons) == 0 && len(zones) == 0 {
+		return info, fmt.Errorf("nenhum sensor encontrado em /sys/class/hwmon ou /sys/class/thermal")
+	}
+
+	return info, nil
+}
+
+func readHwmon(dir string, info *SensorInfo) {
+	chip := readString(filepath.Join(dir, "name"))
+	if chip == "" {
+		chip = readString(filepath.Join(dir, "device/name"))
+	}
+	device := linkBase(filepath.Join(dir, "device"))
+
+	inputs, _ := filepath.Glob(filepath.Join(dir, "*_input"))
+	sort.Slice(inputs, func(i, j int) bool { return naturalLess(inputs[i], inputs[j]) })
+
+	for _, input := range inputs {
+		match := sensorFile.FindStringSubmatch(filepath.Base(input))
+		if match == nil {
+			continue
+		}
+		kind, index := match[1], match[2]
+		prefix := filepath.Join(dir, kind+index)
+
+		raw, ok := readInt(input)
+		if !ok {
+			continue
+		}
+
+		label := readString(prefix + "_label")
+		if label == "" {
+			label = kind + index
+		}
+
+		// temp* está em milésimos de grau, in* em milivolts e fan* já em RPM.
+		scale := 1000.0
+		if kind == "fan" {
+			scale = 1
+		}
+		read := func(suffix string) float64 {
+			v, _ := readInt(prefix + suffix)
+			return float64(v) / scale
+		}
+
+		sensor := Sensor{
+			Chip:    chip,
+			Device:  device,
+			Label:   label,
+			Current: float64(raw) / scale,
+		}
+
+		switch kind {
+		case "temp":
+			sensor.High = read("_max")
+			sensor.Critical = read("_crit")
+			info.Temperatures = append(info.Temperatures, sensor)
+
+			if gpuChips[chip] && info.GPU == 0 {
+				info.GPU = sensor.Current
+			}
+			if diskChips[chip] && index == "1" {
+				info.Disks = append(info.Disks, sensor.Current)
+			}
+		case "fan":
+			sensor.Min = read("_min")
+			sensor.High = read("_max")
+			info.Fans = append(info.Fans, sensor)
+		case "in":
+			sensor.Min = read("_min")
+			sensor.High = read("_max")
+			sensor.Critical = read("_crit")
+			info.Voltages = append(info.Voltages, sensor)
+		}
+	}
+}
+
+func readThermalZone(dir string) (ThermalZone, bool) {
+	temp, ok := readInt(filepath.Join(dir, "temp"))
+	if !ok {
+		return ThermalZone{}, false
+	}
+
+	zone := ThermalZone{
+		Name:        filepath.Base(dir),
+		Type:        readString(filepath.Join(dir, "type")),
+		Temperature: float64(temp) / 1000,
+	}
+
+	trips, _ := filepath.Glob(filepath.Join(dir, "trip_point_*_type"))
+	for _, trip := range trips {
+		if readString(trip) != "critical" {
+			continue
+		}
+		if crit, ok := readInt(strings.TrimSuffix(trip, "_type") + "_temp"); ok {
+			zone.Critical = float64(crit) / 1000
+		}
+	}
+
+	return zone, true
+}
+
+// mapCPUTemperatures escolhe a temperatura do pacote de acordo com o driver:
+// coretemp expõe "Package id N" e "Core N", k10temp/zenpower expõem Tdie
+// (ou Tctl, que em alguns modelos tem offset) e Tccd, e em ARM só existe a
+// thermal zone da SoC.
+func mapCPUTemperatures(info *SensorInfo) {
+	var tdie, tctl float64
+
+	for _, s := range info.Temperatures {
+		if !cpuChips[s.Chip] {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(s.Label, "Package id"):
+			info.CPUPackage = maxFloat(info.CPUPackage, s.Current)
+		case strings.HasPrefix(s.Label, "Core "), strings.HasPrefix(s.Label, "Tccd"):
+			info.CPUCores = append(info.CPUCores, CoreTemperature{Label: s.Label, Temperature: s.Current})
+		case s.Label == "Tdie":
+			tdie = maxFloat(tdie, s.Current)
+		case s.Label == "Tctl":
+			tctl = maxFloat(tctl, s.Current)
+		case s.Chip == "cpu_thermal":
+			info.CPUPackage = maxFloat(info.CPUPackage, s.Current)
+		}
+	}
+
+	if info.CPUPackage == 0 {
+		if tdie > 0 {
+			info.CPUPackage = tdie
+		} else {
+			info.CPUPackage = tctl
+		}
+	}
+
+	if info.CPUPackage == 0 {
+		for _, zone := range info.ThermalZones {
+			switch zone.Type {
+			case "x86_pkg_temp", "cpu-thermal", "cpu_thermal", "soc_thermal", "cpu0-thermal":
+				info.CPUPackage = maxFloat(info.CPUPackage, zone.Temperature)
+			}
+		}
+	}
+
+	if info.CPUPackage == 0 {
+		for _, core := range info.CPUCores {
+			info.CPUPackage = maxFloat(info.CPUPackage, core.Temperature)
+		}
+	}
+}
+
+func readSensorsFallback() (SensorInfo, error) {
+	temps, err := host.SensorsTemperatures()
+	if err != nil {
+		return SensorInfo{}, err
+	}
+
+	var info SensorInfo
+	for _, t := range temps {
+		info.Temperatures = append(info.Temperatures, Sensor{
+			Chip:     t.SensorKey,
+			Label:    t.SensorKey,
+			Current:  t.Temperature,
+			High:     t.High,
+			Critical: t.Critical,
+		})
+	}
+
+	return info, nil
+}
+
+func maxFloat(a, b float64) float64 {
+	if b > a {
+		return b
+	}
+	return a
+}
+
+// naturalLess ordena temp2_input antes de temp10_input.
+func naturalLess(a, b string) bool {
+	na, sa := splitTrailingNumber(a)
+	nb, sb := splitTrailingNumber(b)
+	if sa != sb {
+		return sa < sb
+	}
+	return na < nb
+}
+
+func splitTrailingNumber(path string) (int, string) {
+	name := filepath.Base(path)
+	match := sensorFile.FindStringSubmatch(name)
+	if match == nil {
+		return 0, name
+	}
+	n, _ := strconv.Atoi(match[2])
+	return n, match[1]
+}
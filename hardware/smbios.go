@@ -0,0 +1,193 @@
+package hardware
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type DIMM struct {
+	Slot               string `json:"slot"`
+	Bank               string `json:"bank"`
+	Installed          bool   `json:"installed"`
+	SizeBytes          uint64 `json:"size_bytes"`
+	Type               string `json:"type"`
+	FormFactor         string `json:"form_factor"`
+	SpeedMTs           uint32 `json:"speed_mts"`
+	ConfiguredSpeedMTs uint32 `json:"configured_speed_mts"`
+	Manufacturer       string `json:"manufacturer"`
+	SerialNumber       string `json:"serial_number"`
+	PartNumber         string `json:"part_number"`
+}
+
+type smbiosStructure struct {
+	Type      byte
+	Formatted []byte
+	Strings   []string
+}
+
+var memoryTypes = map[byte]string{
+	0x01: "Other", 0x02: "Unknown", 0x03: "DRAM", 0x04: "EDRAM", 0x05: "VRAM",
+	0x06: "SRAM", 0x07: "RAM", 0x08: "ROM", 0x09: "Flash", 0x0A: "EEPROM",
+	0x0B: "FEPROM", 0x0C: "EPROM", 0x0D: "CDRAM", 0x0E: "3DRAM", 0x0F: "SDRAM",
+	0x10: "SGRAM", 0x11: "RDRAM", 0x12: "DDR", 0x13: "DDR2", 0x14: "DDR2 FB-DIMM",
+	0x18: "DDR3", 0x19: "FBD2", 0x1A: "DDR4", 0x1B: "LPDDR", 0x1C: "LPDDR2",
+	0x1D: "LPDDR3", 0x1E: "LPDDR4", 0x1F: "Logical non-volatile device",
+	0x20: "HBM", 0x21: "HBM2", 0x22: "DDR5", 0x23: "LPDDR5", 0x24: "HBM3",
+}
+
+var memoryFormFactors = map[byte]string{
+	0x01: "Other", 0x02: "Unknown", 0x03: "SIMM", 0x04: "SIP", 0x05: "Chip",
+	0x06: "DIP", 0x07: "ZIP", 0x08: "Proprietary Card", 0x09: "DIMM",
+	0x0A: "TSOP", 0x0B: "Row of chips", 0x0C: "RIMM", 0x0D: "SODIMM",
+	0x0E: "SRIMM", 0x0F: "FB-DIMM", 0x10: "Die",
+}
+
+// readDIMMs lê as estruturas SMBIOS tipo 17 (Memory Device). O kernel expõe
+// cada estrutura separada em /sys/firmware/dmi/entries e a tabela completa
+// em /sys/firmware/dmi/tables/DMI; as duas exigem root.
+func readDIMMs(root string) ([]DIMM, error) {
+	var structures []smbiosStructure
+
+	entries, _ := filepath.Glob(filepath.Join(root, "sys/firmware/dmi/entries/17-*/raw"))
+	sort.Slice(entries, func(i, j int) bool { return dmiEntryLess(entries[i], entries[j]) })
+	for _, entry := range entries {
+		data, err := os.ReadFile(entry)
+		if err != nil {
+			return nil, err
+		}
+		structures = append(structures, parseSMBIOS(data)...)
+	}
+
+	if len(entries) == 0 {
+		data, err := os.ReadFile(filepath.Join(root, "sys/firmware/dmi/tables/DMI"))
+		if err != nil {
+			return nil, err
+		}
+		structures = parseSMBIOS(data)
+	}
+
+	var dimms []DIMM
+	for _, s := range structures {
+		if s.Type == 17 {
+			dimms = append(dimms, parseMemoryDevice(s))
+		}
+	}
+
+	return dimms, nil
+}
+
+func parseSMBIOS(data []byte) []smbiosStructure {
+	var structures []smbiosStructure
+
+	for len(data) >= 4 {
+		length := int(data[1])
+		if length < 4 || length > len(data) {
+			break
+		}
+
+		s := smbiosStructure{Type: data[0], Formatted: data[:length]}
+
+		// A área de strings termina com dois bytes nulos.
+		rest := data[length:]
+		end := strings.Index(string(rest), "\x00\x00")
+		if end < 0 {
+			break
+		}
+		if end > 0 {
+			s.Strings = strings.Split(string(rest[:end]), "\x00")
+		}
+		structures = append(structures, s)
+
+		if s.Type == 127 {
+			break
+		}
+		data = rest[end+2:]
+	}
+
+	return structures
+}
+
+func (s smbiosStructure) byteAt(offset int) byte {
+	if offset >= len(s.Formatted) {
+		return 0
+	}
+	return s.Formatted[offset]
+}
+
+func (s smbiosStructure) wordAt(offset int) uint16 {
+	if offset+2 > len(s.Formatted) {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(s.Formatted[offset:])
+}
+
+func (s smbiosStructure) dwordAt(offset int) uint32 {
+	if offset+4 > len(s.Formatted) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(s.Formatted[offset:])
+}
+
+func (s smbiosStructure) stringAt(offset int) string {
+	index := int(s.byteAt(offset))
+	if index == 0 || index > len(s.Strings) {
+		return ""
+	}
+	return strings.TrimSpace(s.Strings[index-1])
+}
+
+// parseMemoryDevice segue os offsets da especificação SMBIOS 3.x para o
+// tipo 17; campos que não existem em versões antigas ficam zerados.
+func parseMemoryDevice(s smbiosStructure) DIMM {
+	dimm := DIMM{
+		Slot:         s.stringAt(0x10),
+		Bank:         s.stringAt(0x11),
+		Type:         memoryTypes[s.byteAt(0x12)],
+		FormFactor:   memoryFormFactors[s.byteAt(0x0E)],
+		Manufacturer: s.stringAt(0x17),
+		SerialNumber: s.stringAt(0x18),
+		PartNumber:   s.stringAt(0x1A),
+	}
+
+	switch size := s.wordAt(0x0C); {
+	case size == 0:
+	case size == 0xFFFF:
+		dimm.Installed = true
+	case size == 0x7FFF:
+		dimm.Installed = true
+		dimm.SizeBytes = uint64(s.dwordAt(0x1C)&0x7FFFFFFF) * 1024 * 1024
+	case size&0x8000 != 0:
+		dimm.Installed = true
+		dimm.SizeBytes = uint64(size&0x7FFF) * 1024
+	default:
+		dimm.Installed = true
+		dimm.SizeBytes = uint64(size) * 1024 * 1024
+	}
+
+	dimm.SpeedMTs = uint32(s.wordAt(0x15))
+	if dimm.SpeedMTs == 0xFFFF {
+		dimm.SpeedMTs = s.dwordAt(0x54)
+	}
+	dimm.ConfiguredSpeedMTs = uint32(s.wordAt(0x20))
+	if dimm.ConfiguredSpeedMTs == 0xFFFF {
+		dimm.ConfiguredSpeedMTs = s.dwordAt(0x58)
+	}
+
+	// Slots vazios costumam vir com "NO DIMM", "Unknown" ou espaços.
+	if !dimm.Installed {
+		dimm.Manufacturer, dimm.SerialNumber, dimm.PartNumber = "", "", ""
+	}
+
+	return dimm
+}
+
+func dmiEntryLess(a, b string) bool {
+	var na, nb int
+	fmt.Sscanf(filepath.Base(filepath.Dir(a)), "17-%d", &na)
+	fmt.Sscanf(filepath.Base(filepath.Dir(b)), "17-%d", &nb)
+	return na < nb
+}
@@ -0,0 +1,19 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSysfs(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
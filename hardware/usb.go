@@ -0,0 +1,275 @@
+package hardware
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jaypipes/ghw"
+)
+
+var usbIDsPaths = []string{
+	"/usr/share/hwdata/usb.ids",
+	"/usr/share/misc/usb.ids",
+	"/usr/share/usb.ids",
+	"/var/lib/usbutils/usb.ids",
+}
+
+// Usados quando o usb.ids não está instalado.
+var usbClassNames = map[string]string{
+	"01": "Audio",
+	"02": "Communications",
+	"03": "Human Interface Device",
+	"05": "Physical Interface Device",
+	"06": "Imaging",
+	"07": "Printer",
+	"08": "Mass Storage",
+	"09": "Hub",
+	"0a": "CDC Data",
+	"0b": "Chip/SmartCard",
+	"0d": "Content Security",
+	"0e": "Video",
+	"0f": "Personal Healthcare",
+	"10": "Audio/Video",
+	"11": "Billboard",
+	"dc": "Diagnostic",
+	"e0": "Wireless",
+	"ef": "Miscellaneous Device",
+	"fe": "Application Specific Interface",
+	"ff": "Vendor Specific",
+}
+
+type usbIDs struct {
+	vendors  map[string]string
+	products map[string]string
+	classes  map[string]string
+}
+
+var (
+	usbDatabase     *usbIDs
+	usbDatabaseOnce sync.Once
+)
+
+func getUSBInfo() ([]USBDevice, error) {
+	if runtime.GOOS != "linux" {
+		return getRemovableDisks()
+	}
+
+	return readUSBDevices(defaultRoot, loadedUSBIDs())
+}
+
+func loadedUSBIDs() *usbIDs {
+	usbDatabaseOnce.Do(func() {
+		usbDatabase = loadUSBIDs(usbIDsPaths)
+	})
+	return usbDatabase
+}
+
+func readUSBDevices(root string, ids *usbIDs) ([]USBDevice, error) {
+	base := filepath.Join(root, "sys/bus/usb/devices")
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []USBDevice
+	for _, entry := range entries {
+		name := entry.Name()
+		// Entradas com ":" são interfaces e "usbN" são os root hubs.
+		if strings.Contains(name, ":") || strings.HasPrefix(name, "usb") {
+			continue
+		}
+
+		if dev, ok := readUSBDevice(base, name, ids); ok {
+			devices = append(devices, dev)
+		}
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Bus != devices[j].Bus {
+			return devices[i].Bus < devices[j].Bus
+		}
+		return devices[i].Path < devices[j].Path
+	})
+
+	return devices, nil
+}
+
+func readUSBDevice(base, name string, ids *usbIDs) (USBDevice, bool) {
+	dir := filepath.Join(base, name)
+	dev := USBDevice{
+		Path:         name,
+		VendorID:     readString(filepath.Join(dir, "idVendor")),
+		ProductID:    readString(filepath.Join(dir, "idProduct")),
+		Manufacturer: readString(filepath.Join(dir, "manufacturer")),
+		Product:      readString(filepath.Join(dir, "product")),
+		SerialNumber: readString(filepath.Join(dir, "serial")),
+		Class:        readString(filepath.Join(dir, "bDeviceClass")),
+		Speed:        readString(filepath.Join(dir, "speed")),
+	}
+	if dev.VendorID == "" {
+		return USBDevice{}, false
+	}
+	if bus, ok := readUint(filepath.Join(dir, "busnum")); ok {
+		dev.Bus = int(bus)
+	}
+	if num, ok := readUint(filepath.Join(dir, "devnum")); ok {
+		dev.Device = int(num)
+	}
+
+	readUSBInterfaces(base, name, &dev)
+	resolveUSBNames(&dev, ids)
+
+	return dev, true
+}
+
+func resolveUSBNames(dev *USBDevice, ids *usbIDs) {
+	if ids != nil {
+		dev.VendorName = ids.vendors[dev.VendorID]
+		dev.ProductName = ids.products[dev.VendorID+":"+dev.ProductID]
+		dev.ClassName = ids.classes[dev.Class]
+	}
+	if dev.ClassName == "" {
+		dev.ClassName = usbClassNames[dev.Class]
+	}
+
+	dev.Name = firstNonEmpty(dev.Product, dev.ProductName, dev.VendorName, dev.VendorID+":"+dev.ProductID)
+}
+
+// readUSBInterfaces junta as classes e os drivers das interfaces do
+// dispositivo e, quando a classe é definida por interface (bDeviceClass 00),
+// usa a classe da primeira interface.
+func readUSBInterfaces(base, name string, dev *USBDevice) {
+	interfaces, _ := filepath.Glob(filepath.Join(base, name+":*"))
+	sort.Strings(interfaces)
+
+	seen := make(map[string]bool)
+	deviceClass := dev.Class
+	for _, iface := range interfaces {
+		class := readString(filepath.Join(iface, "bInterfaceClass"))
+		if class != "" && !seen["class:"+class] {
+			seen["class:"+class] = true
+			dev.InterfaceClasses = append(dev.InterfaceClasses, class)
+			if deviceClass == "00" || deviceClass == "" {
+				dev.Class = dev.InterfaceClasses[0]
+			}
+		}
+
+		driver := linkBase(filepath.Join(iface, "driver"))
+		if driver != "" && !seen[driver] {
+			seen[driver] = true
+			dev.Drivers = append(dev.Drivers, driver)
+		}
+	}
+}
+
+func loadUSBIDs(paths []string) *usbIDs {
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		ids, err := parseUSBIDs(file)
+		file.Close()
+		if err == nil {
+			return ids
+		}
+	}
+	return nil
+}
+
+// parseUSBIDs lê o formato do usb.ids: fornecedores sem indentação, produtos
+// com um tab e a seção de classes iniciada por "C ".
+func parseUSBIDs(r io.Reader) (*usbIDs, error) {
+	ids := &usbIDs{
+		vendors:  make(map[string]string),
+		products: make(map[string]string),
+		classes:  make(map[string]string),
+	}
+
+	var vendor string
+	var inClasses bool
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "\t") {
+			vendor = ""
+			inClasses = false
+
+			switch {
+			case strings.HasPrefix(line, "C "):
+				fields := strings.SplitN(strings.TrimPrefix(line, "C "), " ", 2)
+				if len(fields) == 2 {
+					ids.classes[strings.ToLower(fields[0])] = strings.TrimSpace(fields[1])
+				}
+				inClasses = true
+			case len(line) > 6 && isHex(line[:4]) && line[4] == ' ':
+				vendor = strings.ToLower(line[:4])
+				ids.vendors[vendor] = strings.TrimSpace(line[4:])
+			}
+			continue
+		}
+
+		if inClasses || vendor == "" || strings.HasPrefix(line, "\t\t") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "\t")
+		if len(line) > 6 && isHex(line[:4]) && line[4] == ' ' {
+			ids.products[vendor+":"+strings.ToLower(line[:4])] = strings.TrimSpace(line[4:])
+		}
+	}
+
+	return ids, scanner.Err()
+}
+
+func isHex(s string) bool {
+	_, err := strconv.ParseUint(s, 16, 64)
+	return err == nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// getRemovableDisks é o que resta fora do Linux: só os discos removíveis
+// que o ghw consegue enxergar, sem os IDs USB.
+func getRemovableDisks() ([]USBDevice, error) {
+	block, err := ghw.Block()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter informações de bloco: %v", err)
+	}
+
+	var devices []USBDevice
+	for _, disk := range block.Disks {
+		if disk.IsRemovable {
+			devices = append(devices, USBDevice{
+				Name:         disk.Model,
+				Manufacturer: disk.Vendor,
+				Product:      disk.Model,
+				SerialNumber: disk.SerialNumber,
+				Class:        "08",
+				ClassName:    usbClassNames["08"],
+			})
+		}
+	}
+
+	return devices, nil
+}
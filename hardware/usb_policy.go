@@ -0,0 +1,136 @@
+package hardware
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	USBVerdictAllowed = "allowed"
+	USBVerdictDenied  = "denied"
+)
+
+// USBRule casa um dispositivo por vendor:product (aceitando "*" no produto),
+// número de série ou classe USB. Campos vazios não restringem.
+type USBRule struct {
+	VendorID  string `json:"vendor_id,omitempty"`
+	ProductID string `json:"product_id,omitempty"`
+	Serial    string `json:"serial,omitempty"`
+	Class     string `json:"class,omitempty"`
+}
+
+type USBPolicy struct {
+	Allow []USBRule `json:"allow"`
+	Deny  []USBRule `json:"deny"`
+}
+
+type USBEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Action    string    `json:"action"`
+	Device    USBDevice `json:"device"`
+	Verdict   string    `json:"verdict"`
+	Rule      string    `json:"rule,omitempty"`
+}
+
+// ParseUSBRules interpreta listas como "0781:5567, 046d:*, serial:ABC123, class:08".
+func ParseUSBRules(spec string) ([]USBRule, error) {
+	var rules []USBRule
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(item, ":")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("regra USB inválida: %q", item)
+		}
+
+		switch strings.ToLower(key) {
+		case "serial":
+			rules = append(rules, USBRule{Serial: value})
+		case "class":
+			rules = append(rules, USBRule{Class: strings.ToLower(value)})
+		default:
+			if !isHex(key) || (value != "*" && !isHex(value)) {
+				return nil, fmt.Errorf("regra USB inválida: %q", item)
+			}
+			rule := USBRule{VendorID: strings.ToLower(key)}
+			if value != "*" {
+				rule.ProductID = strings.ToLower(value)
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+func (r USBRule) Matches(dev USBDevice) bool {
+	if r.VendorID != "" && !strings.EqualFold(r.VendorID, dev.VendorID) {
+		return false
+	}
+	if r.ProductID != "" && !strings.EqualFold(r.ProductID, dev.ProductID) {
+		return false
+	}
+	if r.Serial != "" && r.Serial != dev.SerialNumber {
+		return false
+	}
+	if r.Class != "" && !hasUSBClass(dev, r.Class) {
+		return false
+	}
+	return true
+}
+
+func (r USBRule) String() string {
+	switch {
+	case r.Serial != "":
+		return "serial:" + r.Serial
+	case r.Class != "":
+		return "class:" + r.Class
+	case r.ProductID == "":
+		return r.VendorID + ":*"
+	}
+	return r.VendorID + ":" + r.ProductID
+}
+
+// Evaluate aplica a denylist primeiro; se houver allowlist, qualquer
+// dispositivo fora dela também é negado.
+func (p USBPolicy) Evaluate(dev USBDevice) (string, string) {
+	for _, rule := range p.Deny {
+		if rule.Matches(dev) {
+			return USBVerdictDenied, rule.String()
+		}
+	}
+
+	for _, rule := range p.Allow {
+		if rule.Matches(dev) {
+			return USBVerdictAllowed, rule.String()
+		}
+	}
+
+	if len(p.Allow) > 0 {
+		return USBVerdictDenied, "fora da allowlist"
+	}
+	return USBVerdictAllowed, ""
+}
+
+func (p USBPolicy) Apply(devices []USBDevice) {
+	for i := range devices {
+		devices[i].PolicyVerdict, _ = p.Evaluate(devices[i])
+	}
+}
+
+func hasUSBClass(dev USBDevice, class string) bool {
+	if strings.EqualFold(dev.Class, class) {
+		return true
+	}
+	for _, c := range dev.InterfaceClasses {
+		if strings.EqualFold(c, class) {
+			return true
+		}
+	}
+	return false
+}
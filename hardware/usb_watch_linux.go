@@ -0,0 +1,270 @@
+//go:build linux
+
+package hardware
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// usbSettleDelay é quanto um dispositivo espera pelo "bind" antes de ser
+	// avaliado assim mesmo (kernels anteriores ao 4.14 não mandam "bind").
+	usbSettleDelay = 2 * time.Second
+	usbRecvTimeout = time.Second
+	usbRecvBuffer  = 1 << 20
+)
+
+// WatchUSB escuta os uevents do kernel via netlink e envia um evento para
+// cada dispositivo USB conectado ou removido. Bloqueia até ocorrer um erro
+// de leitura no socket.
+func WatchUSB(policy USBPolicy, events chan<- USBEvent) error {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, syscall.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir socket netlink: %v", err)
+	}
+	defer syscall.Close(fd)
+
+	// O grupo 1 recebe os eventos direto do kernel, sem depender do udev.
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: 1}); err != nil {
+		return fmt.Errorf("erro ao associar socket netlink: %v", err)
+	}
+
+	// Buffer maior para aguentar rajadas (hub com vários dispositivos) e
+	// timeout para conseguir avaliar quem ficou esperando pelo "bind".
+	if err := syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_RCVBUFFORCE, usbRecvBuffer); err != nil {
+		syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_RCVBUF, usbRecvBuffer)
+	}
+	tv := syscall.NsecToTimeval(usbRecvTimeout.Nanoseconds())
+	if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+		return fmt.Errorf("erro ao configurar socket netlink: %v", err)
+	}
+
+	w := newUSBWatcher(policy, defaultRoot, loadedUSBIDs())
+	if devices, err := readUSBDevices(defaultRoot, w.ids); err == nil {
+		for _, dev := range devices {
+			w.known[dev.Path] = dev
+		}
+	}
+
+	buf := make([]byte, 64*1024)
+	for {
+		var batch []USBEvent
+
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		switch err {
+		case nil:
+			batch = w.handle(parseUevent(buf[:n]), time.Now())
+		case syscall.EINTR:
+			continue
+		case syscall.EAGAIN:
+		case syscall.ENOBUFS:
+			// O kernel descartou eventos; o sysfs diz o que mudou.
+			log.Printf("Eventos USB perdidos (buffer do netlink cheio), relendo dispositivos")
+			batch = w.rescan(time.Now())
+		default:
+			return fmt.Errorf("erro ao ler uevent: %v", err)
+		}
+
+		batch = append(batch, w.flush(time.Now())...)
+		for _, event := range batch {
+			events <- event
+		}
+	}
+}
+
+type pendingUSB struct {
+	since time.Time
+	env   map[string]string
+}
+
+// usbWatcher guarda o estado entre uevents. O "add" do usb_device chega
+// antes de o kernel criar as interfaces (N-N:1.0), quando ainda não dá
+// para saber, por exemplo, que é um pendrive; por isso o dispositivo fica
+// pendente até o "bind", que vem depois da configuração.
+type usbWatcher struct {
+	policy  USBPolicy
+	root    string
+	ids     *usbIDs
+	known   map[string]USBDevice
+	pending map[string]pendingUSB
+}
+
+func newUSBWatcher(policy USBPolicy, root string, ids *usbIDs) *usbWatcher {
+	return &usbWatcher{
+		policy:  policy,
+		root:    root,
+		ids:     ids,
+		known:   make(map[string]USBDevice),
+		pending: make(map[string]pendingUSB),
+	}
+}
+
+func (w *usbWatcher) handle(env map[string]string, now time.Time) []USBEvent {
+	if env["SUBSYSTEM"] != "usb" {
+		return nil
+	}
+	name := filepath.Base(env["DEVPATH"])
+
+	switch env["DEVTYPE"] {
+	case "usb_device":
+		switch env["ACTION"] {
+		case "add":
+			if _, ok := w.known[name]; !ok {
+				w.pending[name] = pendingUSB{since: now, env: env}
+			}
+		case "bind":
+			if p, ok := w.pending[name]; ok {
+				delete(w.pending, name)
+				return []USBEvent{w.added(name, p.env, now)}
+			}
+		case "remove":
+			var events []USBEvent
+			if p, ok := w.pending[name]; ok {
+				delete(w.pending, name)
+				events = append(events, w.added(name, p.env, now))
+			}
+			dev, ok := w.known[name]
+			if !ok {
+				dev = usbDeviceFromUevent(name, env, w.ids)
+			}
+			delete(w.known, name)
+			return append(events, w.event("remove", dev, now))
+		}
+
+	case "usb_interface":
+		// Sem "bind", cada interface que aparece pode mudar o veredito;
+		// uma negação é avisada na hora, sem esperar o fim da espera.
+		parent := filepath.Base(filepath.Dir(env["DEVPATH"]))
+		p, ok := w.pending[parent]
+		if env["ACTION"] != "add" || !ok {
+			return nil
+		}
+		dev := w.read(parent, p.env)
+		if verdict, _ := w.policy.Evaluate(dev); verdict == USBVerdictDenied {
+			delete(w.pending, parent)
+			return []USBEvent{w.added(parent, p.env, now)}
+		}
+	}
+
+	return nil
+}
+
+// flush avalia os dispositivos que esperaram o "bind" por tempo demais.
+func (w *usbWatcher) flush(now time.Time) []USBEvent {
+	var events []USBEvent
+	for name, p := range w.pending {
+		if now.Sub(p.since) >= usbSettleDelay {
+			delete(w.pending, name)
+			events = append(events, w.added(name, p.env, now))
+		}
+	}
+	return events
+}
+
+// rescan compara o sysfs com o que já foi informado, depois de eventos
+// perdidos.
+func (w *usbWatcher) rescan(now time.Time) []USBEvent {
+	devices, err := readUSBDevices(w.root, w.ids)
+	if err != nil {
+		log.Printf("Erro ao reler dispositivos USB: %v", err)
+		return nil
+	}
+
+	var events []USBEvent
+	present := make(map[string]bool)
+	for _, dev := range devices {
+		present[dev.Path] = true
+		if _, ok := w.known[dev.Path]; ok {
+			continue
+		}
+		delete(w.pending, dev.Path)
+		w.known[dev.Path] = dev
+		events = append(events, w.event("add", dev, now))
+	}
+
+	for name, dev := range w.known {
+		if !present[name] {
+			delete(w.known, name)
+			events = append(events, w.event("remove", dev, now))
+		}
+	}
+	for name := range w.pending {
+		if !present[name] {
+			delete(w.pending, name)
+		}
+	}
+
+	return events
+}
+
+func (w *usbWatcher) added(name string, env map[string]string, now time.Time) USBEvent {
+	dev := w.read(name, env)
+	w.known[name] = dev
+	return w.event("add", dev, now)
+}
+
+// read lê o dispositivo pelo /sys/bus/usb/devices, onde as interfaces
+// ficam ao lado do dispositivo; se ele já sumiu, usa o uevent.
+func (w *usbWatcher) read(name string, env map[string]string) USBDevice {
+	dev, ok := readUSBDevice(filepath.Join(w.root, "sys/bus/usb/devices"), name, w.ids)
+	if !ok {
+		dev = usbDeviceFromUevent(name, env, w.ids)
+	}
+	return dev
+}
+
+func (w *usbWatcher) event(action string, dev USBDevice, now time.Time) USBEvent {
+	event := USBEvent{Timestamp: now, Action: action, Device: dev}
+	event.Verdict, event.Rule = w.policy.Evaluate(dev)
+	event.Device.PolicyVerdict = event.Verdict
+	return event
+}
+
+// parseUevent interpreta mensagens no formato "add@/devices/...\0ACTION=add\0...".
+func parseUevent(msg []byte) map[string]string {
+	env := make(map[string]string)
+	for _, field := range bytes.Split(msg, []byte{0}) {
+		key, value, ok := strings.Cut(string(field), "=")
+		if ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// usbDeviceFromUevent monta o dispositivo só com o que vem no uevent, para
+// quando o sysfs já não existe mais. PRODUCT tem o formato "46d/c52b/1201".
+func usbDeviceFromUevent(name string, env map[string]string, ids *usbIDs) USBDevice {
+	dev := USBDevice{Path: name}
+
+	product := strings.Split(env["PRODUCT"], "/")
+	if len(product) >= 2 {
+		dev.VendorID = padHex(product[0], 4)
+		dev.ProductID = padHex(product[1], 4)
+	}
+
+	// TYPE vem em decimal: "bDeviceClass/bDeviceSubClass/bDeviceProtocol".
+	var class int
+	if _, err := fmt.Sscanf(env["TYPE"], "%d/", &class); err == nil {
+		dev.Class = fmt.Sprintf("%02x", class)
+	}
+
+	fmt.Sscanf(env["BUSNUM"], "%d", &dev.Bus)
+	fmt.Sscanf(env["DEVNUM"], "%d", &dev.Device)
+
+	resolveUSBNames(&dev, ids)
+	return dev
+}
+
+func padHex(value string, width int) string {
+	if len(value) >= width {
+		return value
+	}
+	return strings.Repeat("0", width-len(value)) + value
+}
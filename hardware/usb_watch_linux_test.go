@@ -0,0 +1,94 @@
+//go:build linux
+
+package hardware
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func usbUevent(action, devtype, devpath string) map[string]string {
+	return map[string]string{"ACTION": action, "SUBSYSTEM": "usb", "DEVTYPE": devtype, "DEVPATH": devpath}
+}
+
+func TestUSBWatcherWaitsForBind(t *testing.T) {
+	root := t.TempDir()
+	devices := filepath.Join(root, "sys/bus/usb/devices")
+	writeSysfs(t, filepath.Join(devices, "1-2"), map[string]string{"idVendor": "0781", "idProduct": "5567", "bDeviceClass": "00"})
+
+	deny, _ := ParseUSBRules("class:08")
+	w := newUSBWatcher(USBPolicy{Deny: deny}, root, nil)
+	now := time.Now()
+
+	if events := w.handle(usbUevent("add", "usb_device", "/devices/pci0000:00/usb1/1-2"), now); len(events) != 0 {
+		t.Fatalf("add não deveria gerar evento antes do bind: %+v", events)
+	}
+
+	// As interfaces só existem depois da configuração do dispositivo.
+	writeSysfs(t, filepath.Join(devices, "1-2:1.0"), map[string]string{"bInterfaceClass": "08"})
+
+	events := w.handle(usbUevent("bind", "usb_device", "/devices/pci0000:00/usb1/1-2"), now)
+	if len(events) != 1 || events[0].Action != "add" {
+		t.Fatalf("esperado um evento add no bind, veio %+v", events)
+	}
+	if events[0].Verdict != USBVerdictDenied {
+		t.Errorf("pendrive deveria ser negado por class:08, veredito %q", events[0].Verdict)
+	}
+
+	events = w.handle(usbUevent("remove", "usb_device", "/devices/pci0000:00/usb1/1-2"), now)
+	if len(events) != 1 || events[0].Action != "remove" || events[0].Device.VendorID != "0781" {
+		t.Errorf("esperado remove com os dados do dispositivo, veio %+v", events)
+	}
+}
+
+func TestUSBWatcherWithoutBind(t *testing.T) {
+	root := t.TempDir()
+	devices := filepath.Join(root, "sys/bus/usb/devices")
+	writeSysfs(t, filepath.Join(devices, "1-3"), map[string]string{"idVendor": "046d", "idProduct": "c52b", "bDeviceClass": "00"})
+
+	deny, _ := ParseUSBRules("class:08")
+	w := newUSBWatcher(USBPolicy{Deny: deny}, root, nil)
+	now := time.Now()
+
+	w.handle(usbUevent("add", "usb_device", "/devices/usb1/1-3"), now)
+	writeSysfs(t, filepath.Join(devices, "1-3:1.0"), map[string]string{"bInterfaceClass": "03"})
+	if events := w.handle(usbUevent("add", "usb_interface", "/devices/usb1/1-3/1-3:1.0"), now); len(events) != 0 {
+		t.Fatalf("interface permitida não deveria antecipar o evento: %+v", events)
+	}
+
+	if events := w.flush(now.Add(usbSettleDelay / 2)); len(events) != 0 {
+		t.Fatalf("flush antes do prazo: %+v", events)
+	}
+	events := w.flush(now.Add(usbSettleDelay))
+	if len(events) != 1 || events[0].Verdict != USBVerdictAllowed {
+		t.Fatalf("esperado add permitido após a espera, veio %+v", events)
+	}
+
+	// Segundo dispositivo: a interface de armazenamento nega na hora.
+	writeSysfs(t, filepath.Join(devices, "1-4"), map[string]string{"idVendor": "0781", "idProduct": "5567", "bDeviceClass": "00"})
+	w.handle(usbUevent("add", "usb_device", "/devices/usb1/1-4"), now)
+	writeSysfs(t, filepath.Join(devices, "1-4:1.0"), map[string]string{"bInterfaceClass": "08"})
+	events = w.handle(usbUevent("add", "usb_interface", "/devices/usb1/1-4/1-4:1.0"), now)
+	if len(events) != 1 || events[0].Verdict != USBVerdictDenied {
+		t.Fatalf("esperado add negado na interface 08, veio %+v", events)
+	}
+}
+
+func TestUSBWatcherRescan(t *testing.T) {
+	root := t.TempDir()
+	devices := filepath.Join(root, "sys/bus/usb/devices")
+	writeSysfs(t, filepath.Join(devices, "2-1"), map[string]string{"idVendor": "1234", "idProduct": "0001"})
+
+	w := newUSBWatcher(USBPolicy{}, root, nil)
+	w.known["2-9"] = USBDevice{Path: "2-9", VendorID: "ffff"}
+
+	events := w.rescan(time.Now())
+	actions := map[string]string{}
+	for _, e := range events {
+		actions[e.Device.Path] = e.Action
+	}
+	if actions["2-1"] != "add" || actions["2-9"] != "remove" || len(events) != 2 {
+		t.Errorf("rescan deveria adicionar 2-1 e remover 2-9, veio %+v", events)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package hardware
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func WatchUSB(policy USBPolicy, events chan<- USBEvent) error {
+	return fmt.Errorf("monitoramento de USB não suportado em %s", runtime.GOOS)
+}
@@ -8,27 +8,54 @@ import (
 	"strings"
 	"time"
 
+	"monitoramento/alert"
+	"monitoramento/anomaly"
 	"monitoramento/hardware"
 	"monitoramento/network"
 	"monitoramento/performance"
+	"monitoramento/sampler"
+	"monitoramento/sbom"
 	"monitoramento/software"
 	"monitoramento/utils"
+	"monitoramento/vuln"
+	"monitoramento/watchdog"
 )
 
 type SystemInfo struct {
-	Timestamp   time.Time           `json:"timestamp"`
-	Hardware    hardware.Info       `json:"hardware"`
-	Software    software.Info       `json:"software"`
-	Network     network.Info        `json:"network"`
-	Performance performance.Metrics `json:"performance"`
+	Timestamp       time.Time           `json:"timestamp"`
+	Hardware        hardware.Info       `json:"hardware"`
+	Software        software.Info       `json:"software"`
+	Network         network.Info        `json:"network"`
+	Performance     performance.Metrics `json:"performance"`
+	Anomalies       []anomaly.Score     `json:"anomalies"`
+	USBEvents       []hardware.USBEvent `json:"usb_events"`
+	Watchdog        []watchdog.Status   `json:"watchdog"`
+	Vulnerabilities []vuln.Finding      `json:"vulnerabilities"`
+	SBOM            *sbom.Report        `json:"sbom,omitempty"`
+}
+
+type agent struct {
+	config          map[string]string
+	serverAddress   string
+	encryptionKey   string
+	dispatcher      *alert.Dispatcher
+	detector        *anomaly.Detector
+	usbPolicy       hardware.USBPolicy
+	hardwareOptions hardware.Options
+	softwareOptions software.Options
+	watchdog        *watchdog.Watchdog
+	vulnDB          *vuln.Database
+	sbomOptions     sbomOptions
+	usbEvents       usbEventLog
 }
 
 func main() {
 	// Ler o arquivo .ini
-	config, err := utils.ReadINIFile("config.ini")
+	sections, err := utils.ReadINISections("config.ini")
 	if err != nil {
 		log.Fatalf("Erro ao ler o arquivo de configuração: %v", err)
 	}
+	config := sections[""]
 
 	serverAddress, ok := config["server_address"]
 	if !ok {
@@ -40,37 +67,112 @@ func main() {
 		log.Fatalf("Chave de criptografia não encontrada no arquivo de configuração")
 	}
 
+	usbPolicy, err := newUSBPolicy(config)
+	if err != nil {
+		log.Fatalf("Erro na política de USB: %v", err)
+	}
+
+	a := &agent{
+		config:          config,
+		serverAddress:   serverAddress,
+		encryptionKey:   encryptionKey,
+		dispatcher:      newAlertDispatcher(config),
+		detector:        newAnomalyDetector(config),
+		usbPolicy:       usbPolicy,
+		hardwareOptions: newHardwareOptions(config),
+		softwareOptions: newSoftwareOptions(config),
+		watchdog:        newWatchdog(sections),
+		vulnDB:          newVulnDatabase(config),
+		sbomOptions:     newSBOMOptions(config),
+	}
+	interval := configSeconds(config, "collection_interval", 0)
+
+	// O monitoramento de hot-plug só faz sentido com o agente rodando continuamente
+	if interval > 0 && config["usb_watch"] != "false" {
+		go a.watchUSB()
+	}
+
+	// Primeira leitura de todos os contadores com uma única espera, para
+	// que o primeiro ciclo já tenha taxas.
+	sampler.Warm(hardware.Prime, performance.Prime, software.Prime, network.Prime)
+
+	for {
+		err := a.runCycle()
+		if interval <= 0 {
+			// Os reinícios rodam em segundo plano; o resultado precisa
+			// ficar no estado salvo antes de o processo sair.
+			a.watchdog.Wait()
+			a.saveWatchdog()
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+			break
+		}
+		if err != nil {
+			log.Printf("%v", err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func (a *agent) runCycle() error {
 	// Coletar informações do sistema
-	info := collectSystemInfo()
+	info := collectSystemInfo(a.hardwareOptions, a.softwareOptions)
+	a.usbPolicy.Apply(info.Hardware.USB)
+	info.USBEvents = a.usbEvents.drain()
+	info.Watchdog = a.watchdog.Check(info.Software.AllProcesses, info.Timestamp)
+	a.saveWatchdog()
+	if a.vulnDB != nil {
+		info.Vulnerabilities = a.vulnDB.Match(info.Software)
+	}
+	a.exportSBOM(&info)
+
+	// Pontuar anomalias contra a linha de base
+	info.Anomalies = a.detector.Observe(info.Performance, info.Timestamp)
+	if stateFile := a.config["anomaly_state_file"]; stateFile != "" {
+		if err := a.detector.Save(stateFile); err != nil {
+			log.Printf("Erro ao salvar linha de base de anomalias: %v", err)
+		}
+	}
+
+	// Avaliar alertas e notificar
+	if err := a.dispatcher.Dispatch(evaluateAlerts(a.config, info)); err != nil {
+		log.Printf("Erro ao enviar alertas: %v", err)
+	}
 
 	// Converter para JSON
 	jsonData, err := json.MarshalIndent(info, "", "  ")
 	if err != nil {
-		log.Fatalf("Erro ao criar JSON: %v", err)
+		return fmt.Errorf("Erro ao criar JSON: %v", err)
 	}
 
 	// Criptografar o JSON
-	encryptedData, err := utils.EncryptJSON(jsonData, encryptionKey)
+	encryptedData, err := utils.EncryptJSON(jsonData, a.encryptionKey)
 	if err != nil {
-		log.Fatalf("Erro ao criptografar os dados: %v", err)
+		return fmt.Errorf("Erro ao criptografar os dados: %v", err)
 	}
 
 	// Enviar dados criptografados para o servidor
-	err = sendDataToServer(serverAddress, encryptedData)
+	err = sendDataToServer(a.serverAddress, encryptedData)
 	if err != nil {
-		log.Fatalf("Erro ao enviar dados para o servidor: %v", err)
+		return fmt.Errorf("Erro ao enviar dados para o servidor: %v", err)
 	}
 
 	fmt.Println("Informações do sistema coletadas, criptografadas e enviadas com sucesso.")
+	return nil
 }
 
-func collectSystemInfo() SystemInfo {
+func collectSystemInfo(hardwareOptions hardware.Options, softwareOptions software.Options) SystemInfo {
+	timestamp := time.Now()
+	hardwareInfo := hardware.Collect(hardwareOptions)
+	performanceMetrics := performance.Collect(hardwareInfo.Sensors)
+	hardwareInfo.CPU.Usage = performanceMetrics.CPUUsage
 	return SystemInfo{
-		Timestamp:   time.Now(),
-		Hardware:    hardware.Collect(),
-		Software:    software.Collect(),
+		Timestamp:   timestamp,
+		Hardware:    hardwareInfo,
+		Software:    software.Collect(softwareOptions),
 		Network:     network.Collect(),
-		Performance: performance.Collect(),
+		Performance: performanceMetrics,
 	}
 }
 
@@ -87,4 +189,3 @@ func sendDataToServer(serverAddress, encryptedData string) error {
 
 	return nil
 }
-
@@ -5,9 +5,10 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
-	"time"
 
 	psnet "github.com/shirou/gopsutil/v3/net"
+
+	"monitoramento/sampler"
 )
 
 type AdvancedNetworkInfo struct {
@@ -123,24 +124,33 @@ func measurePacketLoss(host string) (float64, error) {
 	return 0, fmt.Errorf("não foi possível extrair a porcentagem de perda de pacotes")
 }
 
-func measureNetworkSpeed() (float64, float64, error) {
-	startCounters, err := psnet.IOCounters(false)
-	if err != nil {
-		return 0, 0, err
-	}
+var speedSampler sampler.Sampler
 
-	time.Sleep(5 * time.Second)
+// Prime faz a primeira leitura dos contadores usados por Collect; ver
+// sampler.Warm.
+func Prime() {
+	measureNetworkSpeed()
+}
 
-	endCounters, err := psnet.IOCounters(false)
+// measureNetworkSpeed usa o tráfego total desde o ciclo anterior, em vez de
+// parar a coleta para medir.
+func measureNetworkSpeed() (float64, float64, error) {
+	interval, err := speedSampler.Sample(func() (sampler.Snapshot, error) {
+		counters, err := psnet.IOCounters(false)
+		if err != nil {
+			return nil, err
+		}
+		if len(counters) == 0 {
+			return nil, fmt.Errorf("nenhum contador de rede encontrado")
+		}
+		return sampler.Snapshot{"sent": counters[0].BytesSent, "recv": counters[0].BytesRecv}, nil
+	})
 	if err != nil {
 		return 0, 0, err
 	}
 
-	bytesSent := endCounters[0].BytesSent - startCounters[0].BytesSent
-	bytesRecv := endCounters[0].BytesRecv - startCounters[0].BytesRecv
-
-	downloadSpeed := float64(bytesRecv) / 5 / 1024 / 1024 * 8
-	uploadSpeed := float64(bytesSent) / 5 / 1024 / 1024 * 8
+	downloadSpeed := interval.Rate("recv") / 1024 / 1024 * 8
+	uploadSpeed := interval.Rate("sent") / 1024 / 1024 * 8
 
 	return downloadSpeed, uploadSpeed, nil
 }
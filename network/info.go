@@ -23,6 +23,9 @@ type Interface struct {
 	IPAddresses []string `json:"ip_addresses"`
 	Status      string   `json:"status"`
 	Speed       uint64   `json:"speed_mbps"`
+	Duplex      string   `json:"duplex"`
+	Carrier     bool     `json:"carrier"`
+	MTU         int      `json:"mtu"`
 	BytesSent   uint64   `json:"bytes_sent"`
 	BytesRecv   uint64   `json:"bytes_recv"`
 }
@@ -100,12 +103,17 @@ func getNetworkInterfaces() ([]Interface, error) {
 			}
 		}
 
+		link := readLinkInfo("/", iface.Name)
+
 		networkInterfaces = append(networkInterfaces, Interface{
 			Name:        iface.Name,
 			MACAddress:  iface.HardwareAddr,
 			IPAddresses: ipAddresses,
 			Status:      strings.Join(iface.Flags, ", "),
-			Speed:       uint64(iface.MTU),
+			Speed:       link.Speed,
+			Duplex:      link.Duplex,
+			Carrier:     link.Carrier,
+			MTU:         iface.MTU,
 			BytesSent:   bytesSent,
 			BytesRecv:   bytesRecv,
 		})
@@ -0,0 +1,45 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+type linkInfo struct {
+	Speed   uint64
+	Duplex  string
+	Carrier bool
+}
+
+// readLinkInfo lê a velocidade negociada (Mbps), o duplex e o estado do
+// carrier em /sys/class/net/<if>. Interfaces virtuais ou sem cabo retornam
+// -1 ou erro de leitura na velocidade, que fica zerada.
+func readLinkInfo(root, name string) linkInfo {
+	var link linkInfo
+	if runtime.GOOS != "linux" {
+		return link
+	}
+
+	base := filepath.Join(root, "sys/class/net", name)
+
+	if speed, err := strconv.ParseInt(readSysfs(filepath.Join(base, "speed")), 10, 64); err == nil && speed > 0 {
+		link.Speed = uint64(speed)
+	}
+	if duplex := readSysfs(filepath.Join(base, "duplex")); duplex != "unknown" {
+		link.Duplex = duplex
+	}
+	link.Carrier = readSysfs(filepath.Join(base, "carrier")) == "1"
+
+	return link
+}
+
+func readSysfs(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
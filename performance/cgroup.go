@@ -0,0 +1,155 @@
+package performance
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"monitoramento/sampler"
+	"monitoramento/utils"
+)
+
+// CgroupMetrics traz o consumo de uma slice ou serviço do systemd no cgroup
+// v2. Taxas e porcentagens cobrem o intervalo desde a coleta anterior.
+type CgroupMetrics struct {
+	Path             string       `json:"path"`
+	CPUUsagePercent  float64      `json:"cpu_usage_percent"`
+	ThrottledPercent float64      `json:"throttled_periods_percent"`
+	ThrottledMs      uint64       `json:"throttled_ms"`
+	MemoryCurrent    uint64       `json:"memory_current_bytes"`
+	MemoryMax        uint64       `json:"memory_max_bytes"`
+	MemoryEvents     MemoryEvents `json:"memory_events"`
+	IOReadBytes      uint64       `json:"io_read_bytes_per_sec"`
+	IOWriteBytes     uint64       `json:"io_write_bytes_per_sec"`
+	IOReadIOPS       float64      `json:"io_read_iops"`
+	IOWriteIOPS      float64      `json:"io_write_iops"`
+	Pressure         *PSI         `json:"pressure,omitempty"`
+}
+
+// MemoryEvents são os contadores acumulados de memory.events.
+type MemoryEvents struct {
+	Low     uint64 `json:"low"`
+	High    uint64 `json:"high"`
+	Max     uint64 `json:"max"`
+	OOM     uint64 `json:"oom"`
+	OOMKill uint64 `json:"oom_kill"`
+}
+
+var cgroupSampler sampler.Sampler
+
+// getCgroupMetrics percorre as slices e serviços do systemd (diretórios
+// terminados em .slice ou .service) e calcula o consumo de cada um.
+func getCgroupMetrics(root string) ([]CgroupMetrics, error) {
+	base := utils.CgroupRoot(root)
+	if base == "" {
+		return nil, nil
+	}
+
+	var paths []string
+	err := filepath.WalkDir(base, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".slice") || strings.HasSuffix(d.Name(), ".service") {
+			rel, _ := filepath.Rel(base, path)
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	interval, err := cgroupSampler.Sample(func() (sampler.Snapshot, error) {
+		snapshot := make(sampler.Snapshot)
+		for _, path := range paths {
+			dir := filepath.Join(base, path)
+			for key, value := range utils.ReadKeyValues(filepath.Join(dir, "cpu.stat")) {
+				snapshot[path+":"+key] = value
+			}
+			for key, value := range readIOStat(filepath.Join(dir, "io.stat")) {
+				snapshot[path+":"+key] = value
+			}
+		}
+		return snapshot, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var cgroups []CgroupMetrics
+	for _, path := range paths {
+		dir := filepath.Join(base, path)
+		cg := CgroupMetrics{Path: path}
+
+		if usage, ok := interval.Delta(path + ":usage_usec"); ok && interval.Elapsed > 0 {
+			cg.CPUUsagePercent = float64(usage) / float64(interval.Elapsed.Microseconds()) * 100
+		}
+		if periods, _ := interval.Delta(path + ":nr_periods"); periods > 0 {
+			throttled, _ := interval.Delta(path + ":nr_throttled")
+			cg.ThrottledPercent = float64(throttled) / float64(periods) * 100
+		}
+		throttledUsec, _ := interval.Delta(path + ":throttled_usec")
+		cg.ThrottledMs = throttledUsec / 1000
+
+		cg.MemoryCurrent, _ = utils.ReadCgroupUint(filepath.Join(dir, "memory.current"))
+		// "max" significa sem limite e fica como 0.
+		cg.MemoryMax, _ = utils.ReadCgroupUint(filepath.Join(dir, "memory.max"))
+		events := utils.ReadKeyValues(filepath.Join(dir, "memory.events"))
+		cg.MemoryEvents = MemoryEvents{
+			Low:     events["low"],
+			High:    events["high"],
+			Max:     events["max"],
+			OOM:     events["oom"],
+			OOMKill: events["oom_kill"],
+		}
+
+		cg.IOReadBytes = uint64(interval.Rate(path + ":rbytes"))
+		cg.IOWriteBytes = uint64(interval.Rate(path + ":wbytes"))
+		cg.IOReadIOPS = interval.Rate(path + ":rios")
+		cg.IOWriteIOPS = interval.Rate(path + ":wios")
+
+		if psi, err := readPSI(dir, true); err == nil {
+			cg.Pressure = &psi
+		}
+
+		cgroups = append(cgroups, cg)
+	}
+
+	return cgroups, nil
+}
+
+// readIOStat soma os contadores de todos os dispositivos do io.stat, que tem
+// linhas como "8:0 rbytes=1459200 wbytes=314773504 rios=192 wios=353 ...".
+func readIOStat(path string) map[string]uint64 {
+	totals := make(map[string]uint64)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return totals
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			if v, err := strconv.ParseUint(value, 10, 64); err == nil {
+				totals[key] += v
+			}
+		}
+	}
+
+	return totals
+}
@@ -0,0 +1,179 @@
+package performance
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+
+	"monitoramento/sampler"
+)
+
+type CPUMetrics struct {
+	PerCore         []float64       `json:"per_core_usage_percent"`
+	Times           CPUTimesPercent `json:"times_percent"`
+	ContextSwitches float64         `json:"context_switches_per_sec"`
+	Interrupts      float64         `json:"interrupts_per_sec"`
+}
+
+type CPUTimesPercent struct {
+	User    float64 `json:"user"`
+	System  float64 `json:"system"`
+	Idle    float64 `json:"idle"`
+	Nice    float64 `json:"nice"`
+	IOWait  float64 `json:"iowait"`
+	IRQ     float64 `json:"irq"`
+	SoftIRQ float64 `json:"softirq"`
+	Steal   float64 `json:"steal"`
+}
+
+type kernelCounters struct {
+	ContextSwitches uint64
+	Interrupts      uint64
+}
+
+var cpuTimeFields = []string{"user", "system", "idle", "nice", "iowait", "irq", "softirq", "steal"}
+
+var cpuSampler sampler.Sampler
+
+func getCPUMetrics() (CPUMetrics, float64, error) {
+	var cores []string
+	var kernelErr error
+
+	interval, err := cpuSampler.Sample(func() (sampler.Snapshot, error) {
+		total, err := cpu.Times(false)
+		if err != nil {
+			return nil, err
+		}
+		perCore, err := cpu.Times(true)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot := make(sampler.Snapshot)
+		if len(total) > 0 {
+			addCPUTimes(snapshot, "total", total[0])
+		}
+		cores = cores[:0]
+		for _, t := range perCore {
+			cores = append(cores, t.CPU)
+			addCPUTimes(snapshot, t.CPU, t)
+		}
+
+		var kernel kernelCounters
+		if kernel, kernelErr = readKernelCounters(); kernelErr == nil {
+			snapshot["ctxt"] = kernel.ContextSwitches
+			snapshot["intr"] = kernel.Interrupts
+		}
+		return snapshot, nil
+	})
+	if err != nil {
+		return CPUMetrics{}, 0, err
+	}
+
+	var metrics CPUMetrics
+	metrics.Times = timesPercent(interval, "total")
+	usage := busyPercent(metrics.Times)
+
+	for _, core := range cores {
+		metrics.PerCore = append(metrics.PerCore, busyPercent(timesPercent(interval, core)))
+	}
+
+	if kernelErr == nil {
+		metrics.ContextSwitches = interval.Rate("ctxt")
+		metrics.Interrupts = interval.Rate("intr")
+	} else if runtime.GOOS == "linux" {
+		log.Printf("Erro ao ler trocas de contexto e interrupções: %v", kernelErr)
+	}
+
+	return metrics, usage, nil
+}
+
+// addCPUTimes guarda os tempos em milissegundos; no Linux guest e
+// guest_nice já estão contabilizados em user e nice.
+func addCPUTimes(snapshot sampler.Snapshot, prefix string, t cpu.TimesStat) {
+	values := []float64{t.User, t.System, t.Idle, t.Nice, t.Iowait, t.Irq, t.Softirq, t.Steal}
+	for i, field := range cpuTimeFields {
+		snapshot[prefix+":"+field] = uint64(values[i] * 1000)
+	}
+}
+
+func timesPercent(interval sampler.Interval, prefix string) CPUTimesPercent {
+	deltas := make([]float64, len(cpuTimeFields))
+	var total float64
+	for i, field := range cpuTimeFields {
+		d, _ := interval.Delta(prefix + ":" + field)
+		deltas[i] = float64(d)
+		total += deltas[i]
+	}
+	if total <= 0 {
+		return CPUTimesPercent{}
+	}
+
+	pct := func(i int) float64 { return deltas[i] / total * 100 }
+
+	return CPUTimesPercent{
+		User:    pct(0),
+		System:  pct(1),
+		Idle:    pct(2),
+		Nice:    pct(3),
+		IOWait:  pct(4),
+		IRQ:     pct(5),
+		SoftIRQ: pct(6),
+		Steal:   pct(7),
+	}
+}
+
+func busyPercent(t CPUTimesPercent) float64 {
+	if t == (CPUTimesPercent{}) {
+		return 0
+	}
+	return 100 - t.Idle - t.IOWait
+}
+
+func readKernelCounters() (kernelCounters, error) {
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return kernelCounters{}, err
+	}
+	defer file.Close()
+
+	var counters kernelCounters
+	var found int
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "ctxt":
+			counters.ContextSwitches, err = strconv.ParseUint(fields[1], 10, 64)
+		case "intr":
+			counters.Interrupts, err = strconv.ParseUint(fields[1], 10, 64)
+		default:
+			continue
+		}
+		if err != nil {
+			return kernelCounters{}, fmt.Errorf("erro ao interpretar /proc/stat: %v", err)
+		}
+		found++
+	}
+	if err := scanner.Err(); err != nil {
+		return kernelCounters{}, err
+	}
+
+	if found < 2 {
+		return kernelCounters{}, fmt.Errorf("contadores ctxt/intr não encontrados em /proc/stat")
+	}
+
+	return counters, nil
+}
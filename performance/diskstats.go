@@ -0,0 +1,241 @@
+package performance
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	"monitoramento/sampler"
+)
+
+type DiskDeviceIO struct {
+	Device           string  `json:"device"`
+	ReadBytesPerSec  uint64  `json:"read_bytes_per_sec"`
+	WriteBytesPerSec uint64  `json:"write_bytes_per_sec"`
+	ReadIOPS         float64 `json:"read_iops"`
+	WriteIOPS        float64 `json:"write_iops"`
+	AvgAwaitMs       float64 `json:"avg_await_ms"`
+	UtilPercent      float64 `json:"util_percent"`
+	InFlight         uint64  `json:"in_flight"`
+}
+
+type diskStat struct {
+	Reads        uint64
+	SectorsRead  uint64
+	ReadTimeMs   uint64
+	Writes       uint64
+	SectorsWrite uint64
+	WriteTimeMs  uint64
+	InFlight     uint64
+	IOTimeMs     uint64
+}
+
+var diskSampler sampler.Sampler
+
+// diskStatsIO calcula as métricas por disco a partir do /proc/diskstats,
+// comparando com a leitura guardada no ciclo anterior.
+func diskStatsIO(root string) ([]DiskDeviceIO, error) {
+	var current map[string]diskStat
+
+	interval, err := diskSampler.Sample(func() (sampler.Snapshot, error) {
+		stats, err := readDiskStats(root)
+		if err != nil {
+			return nil, err
+		}
+		current = stats
+
+		snapshot := make(sampler.Snapshot)
+		for name, s := range stats {
+			snapshot[name+":reads"] = s.Reads
+			snapshot[name+":sectors_read"] = s.SectorsRead
+			snapshot[name+":read_ms"] = s.ReadTimeMs
+			snapshot[name+":writes"] = s.Writes
+			snapshot[name+":sectors_written"] = s.SectorsWrite
+			snapshot[name+":write_ms"] = s.WriteTimeMs
+			snapshot[name+":io_ms"] = s.IOTimeMs
+		}
+		return snapshot, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	elapsedMs := float64(interval.Elapsed.Milliseconds())
+
+	var devices []DiskDeviceIO
+	for name, stat := range current {
+		reads, ok := interval.Delta(name + ":reads")
+		if !ok {
+			continue
+		}
+		writes, _ := interval.Delta(name + ":writes")
+
+		dev := DiskDeviceIO{
+			Device:           name,
+			ReadBytesPerSec:  uint64(interval.Rate(name+":sectors_read") * 512),
+			WriteBytesPerSec: uint64(interval.Rate(name+":sectors_written") * 512),
+			ReadIOPS:         interval.Rate(name + ":reads"),
+			WriteIOPS:        interval.Rate(name + ":writes"),
+			InFlight:         stat.InFlight,
+		}
+		if ioMs, _ := interval.Delta(name + ":io_ms"); elapsedMs > 0 {
+			dev.UtilPercent = float64(ioMs) / elapsedMs * 100
+			if dev.UtilPercent > 100 {
+				dev.UtilPercent = 100
+			}
+		}
+		if ios := reads + writes; ios > 0 {
+			readMs, _ := interval.Delta(name + ":read_ms")
+			writeMs, _ := interval.Delta(name + ":write_ms")
+			dev.AvgAwaitMs = float64(readMs+writeMs) / float64(ios)
+		}
+
+		devices = append(devices, dev)
+	}
+
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Device < devices[j].Device })
+	return devices, nil
+}
+
+func readDiskStats(root string) (map[string]diskStat, error) {
+	file, err := os.Open(filepath.Join(root, "proc/diskstats"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stats, err := parseDiskStats(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// Só discos inteiros entram, para não somar a partição junto com o
+	// disco; loop, ram e zram também ficam de fora.
+	for name := range stats {
+		if !isWholeDisk(root, name) {
+			delete(stats, name)
+		}
+	}
+
+	return stats, nil
+}
+
+func parseDiskStats(r io.Reader) (map[string]diskStat, error) {
+	stats := make(map[string]diskStat)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 14 {
+			continue
+		}
+
+		values := make([]uint64, 11)
+		for i := range values {
+			v, err := strconv.ParseUint(fields[3+i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao interpretar /proc/diskstats: %v", err)
+			}
+			values[i] = v
+		}
+
+		stats[fields[2]] = diskStat{
+			Reads:        values[0],
+			SectorsRead:  values[2],
+			ReadTimeMs:   values[3],
+			Writes:       values[4],
+			SectorsWrite: values[6],
+			WriteTimeMs:  values[7],
+			InFlight:     values[8],
+			IOTimeMs:     values[9],
+		}
+	}
+
+	return stats, scanner.Err()
+}
+
+func isWholeDisk(root, name string) bool {
+	for _, prefix := range []string{"loop", "ram", "zram"} {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+	// Partições não aparecem em /sys/block; "/" no nome vira "!" no sysfs.
+	_, err := os.Stat(filepath.Join(root, "sys/block", strings.ReplaceAll(name, "/", "!")))
+	return err == nil
+}
+
+// diskCountersIO é o caminho usado fora do Linux, com os contadores do
+// gopsutil; não há await, utilização nem fila nesses sistemas.
+func diskCountersIO() ([]DiskDeviceIO, error) {
+	var names []string
+
+	interval, err := diskSampler.Sample(func() (sampler.Snapshot, error) {
+		counters, err := disk.IOCounters()
+		if err != nil {
+			return nil, err
+		}
+
+		names = names[:0]
+		snapshot := make(sampler.Snapshot)
+		for name, c := range counters {
+			names = append(names, name)
+			snapshot[name+":reads"] = c.ReadCount
+			snapshot[name+":read_bytes"] = c.ReadBytes
+			snapshot[name+":writes"] = c.WriteCount
+			snapshot[name+":write_bytes"] = c.WriteBytes
+		}
+		return snapshot, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []DiskDeviceIO
+	for _, name := range names {
+		if _, ok := interval.Delta(name + ":reads"); !ok {
+			continue
+		}
+		devices = append(devices, DiskDeviceIO{
+			Device:           name,
+			ReadBytesPerSec:  uint64(interval.Rate(name + ":read_bytes")),
+			WriteBytesPerSec: uint64(interval.Rate(name + ":write_bytes")),
+			ReadIOPS:         interval.Rate(name + ":reads"),
+			WriteIOPS:        interval.Rate(name + ":writes"),
+		})
+	}
+
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Device < devices[j].Device })
+	return devices, nil
+}
+
+// aggregateDiskIO soma os dispositivos para os totais, pulando os que são
+// montados sobre outros discos (device-mapper, md), cujo tráfego já aparece
+// nos discos de baixo.
+func aggregateDiskIO(root string, devices []DiskDeviceIO) DiskIOMetrics {
+	metrics := DiskIOMetrics{Devices: devices}
+
+	for _, dev := range devices {
+		if hasSlaves(root, dev.Device) {
+			continue
+		}
+		metrics.ReadBytes += dev.ReadBytesPerSec
+		metrics.WriteBytes += dev.WriteBytesPerSec
+		metrics.IOPSRead += uint64(dev.ReadIOPS)
+		metrics.IOPSWrite += uint64(dev.WriteIOPS)
+	}
+
+	return metrics
+}
+
+func hasSlaves(root, name string) bool {
+	entries, err := os.ReadDir(filepath.Join(root, "sys/block", strings.ReplaceAll(name, "/", "!"), "slaves"))
+	return err == nil && len(entries) > 0
+}
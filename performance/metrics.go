@@ -2,38 +2,41 @@ package performance
 
 import (
 	"log"
-	"strings"
-	"time"
+	"runtime"
 
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/disk"
-	"github.com/shirou/gopsutil/v3/host"
 	"github.com/shirou/gopsutil/v3/load"
 	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/net"
+
+	"monitoramento/hardware"
+	"monitoramento/sampler"
 )
 
 type Metrics struct {
     CPUUsage     float64         `json:"cpu_usage_percent"`
+    CPU          CPUMetrics      `json:"cpu"`
     MemoryUsage  float64         `json:"memory_usage_percent"`
     DiskIO       DiskIOMetrics   `json:"disk_io"`
     NetworkIO    NetworkIOMetrics `json:"network_io"`
     SystemLoad   []float64       `json:"system_load"`
     Temperatures Temperatures    `json:"temperatures"`
+    Pressure     PSI             `json:"pressure"`
+    Cgroups      []CgroupMetrics `json:"cgroups"`
 }
 
 type DiskIOMetrics struct {
-    ReadBytes  uint64 `json:"read_bytes_per_sec"`
-    WriteBytes uint64 `json:"write_bytes_per_sec"`
-    IOPSRead   uint64 `json:"iops_read"`
-    IOPSWrite  uint64 `json:"iops_write"`
+    ReadBytes  uint64         `json:"read_bytes_per_sec"`
+    WriteBytes uint64         `json:"write_bytes_per_sec"`
+    IOPSRead   uint64         `json:"iops_read"`
+    IOPSWrite  uint64         `json:"iops_write"`
+    Devices    []DiskDeviceIO `json:"devices"`
 }
 
 type NetworkIOMetrics struct {
-    BytesSent   uint64 `json:"bytes_sent_per_sec"`
-    BytesRecv   uint64 `json:"bytes_recv_per_sec"`
-    PacketsSent uint64 `json:"packets_sent_per_sec"`
-    PacketsRecv uint64 `json:"packets_recv_per_sec"`
+    BytesSent   uint64        `json:"bytes_sent_per_sec"`
+    BytesRecv   uint64        `json:"bytes_recv_per_sec"`
+    PacketsSent uint64        `json:"packets_sent_per_sec"`
+    PacketsRecv uint64        `json:"packets_recv_per_sec"`
+    Interfaces  []InterfaceIO `json:"interfaces"`
 }
 
 type Temperatures struct {
@@ -42,11 +45,13 @@ type Temperatures struct {
     Disk []float64 `json:"disk_celsius"`
 }
 
-func Collect() Metrics {
+// Collect recebe os sensores já lidos pela coleta de hardware no mesmo
+// ciclo, para não varrer o hwmon duas vezes.
+func Collect(sensors hardware.SensorInfo) Metrics {
 	var metrics Metrics
 	var err error
 
-	metrics.CPUUsage, err = getCPUUsage()
+	metrics.CPU, metrics.CPUUsage, err = getCPUMetrics()
 	if err != nil {
 		log.Printf("Erro ao coletar uso da CPU: %v", err)
 	}
@@ -71,20 +76,37 @@ func Collect() Metrics {
 		log.Printf("Erro ao coletar carga do sistema: %v", err)
 	}
 
-	metrics.Temperatures, err = getTemperatures()
-	if err != nil {
-		log.Printf("Erro ao coletar temperaturas: %v", err)
+	metrics.Temperatures = Temperatures{
+		CPU:  sensors.CPUPackage,
+		GPU:  sensors.GPU,
+		Disk: sensors.Disks,
+	}
+
+	// PSI e cgroup v2 só existem no Linux.
+	if runtime.GOOS == "linux" {
+		metrics.Pressure, err = readPSI("/proc/pressure", false)
+		if err != nil {
+			log.Printf("Erro ao coletar pressão de recursos (PSI): %v", err)
+		}
+
+		metrics.Cgroups, err = getCgroupMetrics("/")
+		if err != nil {
+			log.Printf("Erro ao coletar métricas de cgroups: %v", err)
+		}
 	}
 
 	return metrics
 }
 
-func getCPUUsage() (float64, error) {
-	percent, err := cpu.Percent(time.Second, false)
-	if err != nil {
-		return 0, err
+// Prime faz a primeira leitura dos contadores usados por Collect; ver
+// sampler.Warm.
+func Prime() {
+	getCPUMetrics()
+	getDiskIO()
+	getNetworkIO()
+	if runtime.GOOS == "linux" {
+		getCgroupMetrics("/")
 	}
-	return percent[0], nil
 }
 
 func getMemoryUsage() (float64, error) {
@@ -96,58 +118,33 @@ func getMemoryUsage() (float64, error) {
 }
 
 func getDiskIO() (DiskIOMetrics, error) {
-	before, err := disk.IOCounters()
-	if err != nil {
-		return DiskIOMetrics{}, err
-	}
-
-	time.Sleep(time.Second)
+	var devices []DiskDeviceIO
+	var err error
 
-	after, err := disk.IOCounters()
+	if runtime.GOOS == "linux" {
+		devices, err = diskStatsIO("/")
+	} else {
+		devices, err = diskCountersIO()
+	}
 	if err != nil {
 		return DiskIOMetrics{}, err
 	}
 
-	var totalReadBytes, totalWriteBytes, totalReadCount, totalWriteCount uint64
-
-	for device, afterStat := range after {
-		beforeStat, ok := before[device]
-		if !ok {
-			continue
-		}
-		totalReadBytes += afterStat.ReadBytes - beforeStat.ReadBytes
-		totalWriteBytes += afterStat.WriteBytes - beforeStat.WriteBytes
-		totalReadCount += afterStat.ReadCount - beforeStat.ReadCount
-		totalWriteCount += afterStat.WriteCount - beforeStat.WriteCount
-	}
-
-	return DiskIOMetrics{
-		ReadBytes:  totalReadBytes,
-		WriteBytes: totalWriteBytes,
-		IOPSRead:   totalReadCount,
-		IOPSWrite:  totalWriteCount,
-	}, nil
+	return aggregateDiskIO("/", devices), nil
 }
 
 func getNetworkIO() (NetworkIOMetrics, error) {
-	before, err := net.IOCounters(false)
-	if err != nil {
-		return NetworkIOMetrics{}, err
+	read := netCounters
+	if runtime.GOOS == "linux" {
+		read = func() (sampler.Snapshot, error) { return readNetDev("/") }
 	}
 
-	time.Sleep(time.Second)
-
-	after, err := net.IOCounters(false)
+	interfaces, err := interfacesIO(read)
 	if err != nil {
 		return NetworkIOMetrics{}, err
 	}
 
-	return NetworkIOMetrics{
-		BytesSent:   after[0].BytesSent - before[0].BytesSent,
-		BytesRecv:   after[0].BytesRecv - before[0].BytesRecv,
-		PacketsSent: after[0].PacketsSent - before[0].PacketsSent,
-		PacketsRecv: after[0].PacketsRecv - before[0].PacketsRecv,
-	}, nil
+	return aggregateNetworkIO(interfaces), nil
 }
 
 func getSystemLoad() ([]float64, error) {
@@ -157,30 +154,3 @@ func getSystemLoad() ([]float64, error) {
 	}
 	return []float64{loadAvg.Load1, loadAvg.Load5, loadAvg.Load15}, nil
 }
-
-func getTemperatures() (Temperatures, error) {
-	temps, err := host.SensorsTemperatures()
-	if err != nil {
-		return Temperatures{}, err
-	}
-
-	var cpuTemp, gpuTemp float64
-	var diskTemps []float64
-
-	for _, temp := range temps {
-		if strings.HasPrefix(temp.SensorKey, "coretemp") {
-			cpuTemp = temp.Temperature
-		} else if strings.HasPrefix(temp.SensorKey, "acpitz") {
-			gpuTemp = temp.Temperature
-		} else if strings.HasPrefix(temp.SensorKey, "nvme") {
-			diskTemps = append(diskTemps, temp.Temperature)
-		}
-	}
-
-	return Temperatures{
-		CPU:  cpuTemp,
-		GPU:  gpuTemp,
-		Disk: diskTemps,
-	}, nil
-}
-
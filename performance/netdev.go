@@ -0,0 +1,171 @@
+package performance
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/net"
+
+	"monitoramento/sampler"
+)
+
+// InterfaceIO traz as taxas por segundo de cada interface. Erros, descartes,
+// FIFO e colisões são a quantidade ocorrida desde a coleta anterior.
+type InterfaceIO struct {
+	Name        string `json:"name"`
+	BytesSent   uint64 `json:"bytes_sent_per_sec"`
+	BytesRecv   uint64 `json:"bytes_recv_per_sec"`
+	PacketsSent uint64 `json:"packets_sent_per_sec"`
+	PacketsRecv uint64 `json:"packets_recv_per_sec"`
+	ErrorsIn    uint64 `json:"errors_in"`
+	ErrorsOut   uint64 `json:"errors_out"`
+	DropsIn     uint64 `json:"drops_in"`
+	DropsOut    uint64 `json:"drops_out"`
+	FIFOIn      uint64 `json:"fifo_in"`
+	FIFOOut     uint64 `json:"fifo_out"`
+	Collisions  uint64 `json:"collisions"`
+}
+
+var netDevFields = []string{
+	"bytes_recv", "packets_recv", "errors_in", "drops_in", "fifo_in",
+	"bytes_sent", "packets_sent", "errors_out", "drops_out", "fifo_out", "collisions",
+}
+
+var netSampler sampler.Sampler
+
+// interfacesIO calcula as métricas por interface comparando com a leitura
+// guardada no ciclo anterior. No Linux os contadores vêm do /proc/net/dev;
+// nos outros sistemas, do gopsutil, que não informa colisões.
+func interfacesIO(read func() (sampler.Snapshot, error)) ([]InterfaceIO, error) {
+	var names []string
+
+	interval, err := netSampler.Sample(func() (sampler.Snapshot, error) {
+		snapshot, err := read()
+		if err != nil {
+			return nil, err
+		}
+
+		names = names[:0]
+		for key := range snapshot {
+			if name, ok := strings.CutSuffix(key, ":bytes_recv"); ok {
+				names = append(names, name)
+			}
+		}
+		return snapshot, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delta := func(key string) uint64 {
+		d, _ := interval.Delta(key)
+		return d
+	}
+
+	var interfaces []InterfaceIO
+	for _, name := range names {
+		if _, ok := interval.Delta(name + ":bytes_recv"); !ok {
+			continue
+		}
+
+		interfaces = append(interfaces, InterfaceIO{
+			Name:        name,
+			BytesSent:   uint64(interval.Rate(name + ":bytes_sent")),
+			BytesRecv:   uint64(interval.Rate(name + ":bytes_recv")),
+			PacketsSent: uint64(interval.Rate(name + ":packets_sent")),
+			PacketsRecv: uint64(interval.Rate(name + ":packets_recv")),
+			ErrorsIn:    delta(name + ":errors_in"),
+			ErrorsOut:   delta(name + ":errors_out"),
+			DropsIn:     delta(name + ":drops_in"),
+			DropsOut:    delta(name + ":drops_out"),
+			FIFOIn:      delta(name + ":fifo_in"),
+			FIFOOut:     delta(name + ":fifo_out"),
+			Collisions:  delta(name + ":collisions"),
+		})
+	}
+
+	sort.Slice(interfaces, func(i, j int) bool { return interfaces[i].Name < interfaces[j].Name })
+	return interfaces, nil
+}
+
+func readNetDev(root string) (sampler.Snapshot, error) {
+	file, err := os.Open(filepath.Join(root, "proc/net/dev"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseNetDev(file)
+}
+
+// parseNetDev lê o /proc/net/dev. Depois do nome vêm 8 colunas de recepção
+// (bytes packets errs drop fifo frame compressed multicast) e 8 de envio
+// (bytes packets errs drop fifo colls carrier compressed).
+func parseNetDev(r io.Reader) (sampler.Snapshot, error) {
+	snapshot := make(sampler.Snapshot)
+	columns := []int{0, 1, 2, 3, 4, 8, 9, 10, 11, 12, 13}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 16 {
+			continue
+		}
+		name = strings.TrimSpace(name)
+
+		for i, column := range columns {
+			v, err := strconv.ParseUint(fields[column], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao interpretar /proc/net/dev: %v", err)
+			}
+			snapshot[name+":"+netDevFields[i]] = v
+		}
+	}
+
+	return snapshot, scanner.Err()
+}
+
+func netCounters() (sampler.Snapshot, error) {
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make(sampler.Snapshot)
+	for _, c := range counters {
+		snapshot[c.Name+":bytes_recv"] = c.BytesRecv
+		snapshot[c.Name+":packets_recv"] = c.PacketsRecv
+		snapshot[c.Name+":errors_in"] = c.Errin
+		snapshot[c.Name+":drops_in"] = c.Dropin
+		snapshot[c.Name+":fifo_in"] = c.Fifoin
+		snapshot[c.Name+":bytes_sent"] = c.BytesSent
+		snapshot[c.Name+":packets_sent"] = c.PacketsSent
+		snapshot[c.Name+":errors_out"] = c.Errout
+		snapshot[c.Name+":drops_out"] = c.Dropout
+		snapshot[c.Name+":fifo_out"] = c.Fifoout
+	}
+	return snapshot, nil
+}
+
+func aggregateNetworkIO(interfaces []InterfaceIO) NetworkIOMetrics {
+	metrics := NetworkIOMetrics{Interfaces: interfaces}
+
+	for _, iface := range interfaces {
+		metrics.BytesSent += iface.BytesSent
+		metrics.BytesRecv += iface.BytesRecv
+		metrics.PacketsSent += iface.PacketsSent
+		metrics.PacketsRecv += iface.PacketsRecv
+	}
+
+	return metrics
+}
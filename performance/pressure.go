@@ -0,0 +1,114 @@
+package performance
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PressureStats segue o formato do PSI: porcentagem do tempo com tarefas
+// paradas esperando o recurso nas janelas de 10s, 60s e 300s, e o total
+// acumulado em microssegundos.
+type PressureStats struct {
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	Total  uint64  `json:"total_usec"`
+}
+
+// Pressure separa "some" (pelo menos uma tarefa parada) de "full" (todas
+// as tarefas paradas ao mesmo tempo).
+type Pressure struct {
+	Some PressureStats `json:"some"`
+	Full PressureStats `json:"full"`
+}
+
+type PSI struct {
+	CPU    Pressure `json:"cpu"`
+	Memory Pressure `json:"memory"`
+	IO     Pressure `json:"io"`
+}
+
+// readPSI lê os arquivos cpu, memory e io de dir, que pode ser /proc/pressure
+// ou o diretório de um cgroup (cpu.pressure, memory.pressure, io.pressure).
+func readPSI(dir string, cgroup bool) (PSI, error) {
+	var psi PSI
+
+	for _, r := range []struct {
+		name   string
+		target *Pressure
+	}{
+		{"cpu", &psi.CPU},
+		{"memory", &psi.Memory},
+		{"io", &psi.IO},
+	} {
+		name := r.name
+		if cgroup {
+			name += ".pressure"
+		}
+
+		file, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return PSI{}, err
+		}
+		*r.target, err = parsePressure(file)
+		file.Close()
+		if err != nil {
+			return PSI{}, err
+		}
+	}
+
+	return psi, nil
+}
+
+// parsePressure interpreta linhas como
+// "some avg10=0.42 avg60=0.79 avg300=0.79 total=21396947".
+func parsePressure(r io.Reader) (Pressure, error) {
+	var p Pressure
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var stats *PressureStats
+		switch fields[0] {
+		case "some":
+			stats = &p.Some
+		case "full":
+			stats = &p.Full
+		default:
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+
+			var err error
+			switch key {
+			case "avg10":
+				stats.Avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				stats.Avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				stats.Avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				stats.Total, err = strconv.ParseUint(value, 10, 64)
+			}
+			if err != nil {
+				return Pressure{}, fmt.Errorf("erro ao interpretar pressão %q: %v", field, err)
+			}
+		}
+	}
+
+	return p, scanner.Err()
+}
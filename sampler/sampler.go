@@ -0,0 +1,143 @@
+package sampler
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// Warmup é o intervalo usado só na primeira amostra do processo, quando
+// ainda não existe leitura anterior para comparar.
+const Warmup = time.Second
+
+// ErrPriming é devolvido por Sample durante Warm, quando a chamada só
+// guardou a primeira leitura.
+var ErrPriming = errors.New("primeira leitura guardada, sem intervalo ainda")
+
+var warm struct {
+	mu      sync.Mutex
+	priming bool
+	primed  bool
+}
+
+// Warm faz a primeira leitura de todos os samplers de uma vez, para que o
+// Warmup seja esperado uma única vez em vez de uma vez por Sampler. Cada
+// função de prime deve passar pelas coletas que usam Sample; durante elas,
+// o Sample de um Sampler ainda sem leitura só guarda o snapshot e devolve
+// ErrPriming.
+func Warm(prime ...func()) {
+	warm.mu.Lock()
+	warm.priming, warm.primed = true, false
+	warm.mu.Unlock()
+
+	for _, p := range prime {
+		p()
+	}
+
+	warm.mu.Lock()
+	warm.priming = false
+	primed := warm.primed
+	warm.mu.Unlock()
+
+	if primed {
+		time.Sleep(Warmup)
+	}
+}
+
+// priming marca que um Sampler guardou a primeira leitura dentro de Warm.
+func priming() bool {
+	warm.mu.Lock()
+	defer warm.mu.Unlock()
+	if warm.priming {
+		warm.primed = true
+	}
+	return warm.priming
+}
+
+// Snapshot é uma leitura de contadores crus (sempre crescentes), indexados
+// por nome.
+type Snapshot map[string]uint64
+
+// Interval traz a diferença de cada contador entre duas amostras e o tempo
+// real decorrido entre elas.
+type Interval struct {
+	Elapsed time.Duration
+	deltas  map[string]uint64
+}
+
+// Delta retorna quanto o contador andou no intervalo; false quando ele não
+// existia na amostra anterior.
+func (i Interval) Delta(key string) (uint64, bool) {
+	d, ok := i.deltas[key]
+	return d, ok
+}
+
+// Rate retorna a variação do contador por segundo.
+func (i Interval) Rate(key string) float64 {
+	seconds := i.Elapsed.Seconds()
+	if seconds <= 0 {
+		return 0
+	}
+	return float64(i.deltas[key]) / seconds
+}
+
+// Sampler guarda a leitura anterior entre os ciclos de coleta, de forma que
+// as taxas cobrem todo o intervalo entre um ciclo e outro sem precisar
+// dormir dentro do coletor.
+type Sampler struct {
+	mu     sync.Mutex
+	prev   Snapshot
+	prevAt time.Time
+}
+
+// Sample lê os contadores e devolve o intervalo desde a amostra anterior.
+// Na primeira chamada fora de Warm lê duas vezes com Warmup de espera entre
+// elas.
+func (s *Sampler) Sample(read func() (Snapshot, error)) (Interval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := read()
+	if err != nil {
+		return Interval{}, err
+	}
+	now := time.Now()
+
+	if s.prev == nil {
+		s.prev, s.prevAt = current, now
+		if priming() {
+			return Interval{}, ErrPriming
+		}
+		time.Sleep(Warmup)
+		if current, err = read(); err != nil {
+			return Interval{}, err
+		}
+		now = time.Now()
+	}
+
+	interval := Interval{Elapsed: now.Sub(s.prevAt), deltas: make(map[string]uint64, len(current))}
+	for key, value := range current {
+		if before, ok := s.prev[key]; ok {
+			interval.deltas[key] = Delta(before, value)
+		}
+	}
+
+	s.prev, s.prevAt = current, now
+	return interval, nil
+}
+
+// Delta calcula a diferença entre duas leituras de um contador. Se o valor
+// diminuiu e o anterior estava na metade de cima da faixa de 32 bits, o
+// contador deu a volta (contadores de 32 bits ainda aparecem em kernels e
+// drivers antigos); qualquer outra queda é tratada como reinício do
+// contador, e a diferença é o próprio valor atual.
+func Delta(before, after uint64) uint64 {
+	if after >= before {
+		return after - before
+	}
+	if before <= math.MaxUint32 && before > math.MaxUint32/2 && after <= math.MaxUint32 {
+		return after + (math.MaxUint32 - before) + 1
+	}
+	return after
+}
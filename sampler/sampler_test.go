@@ -0,0 +1,102 @@
+package sampler
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDelta(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after uint64
+		want          uint64
+	}{
+		{"crescente", 100, 250, 150},
+		{"igual", 4096, 4096, 0},
+		{"volta em 32 bits", math.MaxUint32 - 9, 5, 15},
+		{"volta no limite de 32 bits", math.MaxUint32, 0, 1},
+		{"reinício em 32 bits", 1000, 10, 10},
+		{"reinício em 64 bits", 1 << 40, 300, 300},
+		{"queda de 64 bits para 32 bits", math.MaxUint64, 7, 7},
+	}
+
+	for _, tt := range tests {
+		if got := Delta(tt.before, tt.after); got != tt.want {
+			t.Errorf("%s: Delta(%d, %d) = %d, esperado %d", tt.name, tt.before, tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestSampleRate(t *testing.T) {
+	var s Sampler
+	readings := []Snapshot{
+		{"bytes": 1000, "pacotes": 10},
+		{"bytes": 3000, "pacotes": 10, "novo": 50},
+	}
+	read := func() (Snapshot, error) {
+		snapshot := readings[0]
+		readings = readings[1:]
+		return snapshot, nil
+	}
+
+	Warm(func() {
+		if _, err := s.Sample(read); err != ErrPriming {
+			t.Errorf("esperado ErrPriming na primeira leitura, obtido %v", err)
+		}
+	})
+
+	interval, err := s.Sample(read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if interval.Elapsed < Warmup {
+		t.Errorf("esperado intervalo de pelo menos %v, obtido %v", Warmup, interval.Elapsed)
+	}
+
+	if d, ok := interval.Delta("bytes"); !ok || d != 2000 {
+		t.Errorf("esperado delta de 2000 bytes, obtido %d (%v)", d, ok)
+	}
+	if _, ok := interval.Delta("novo"); ok {
+		t.Error("contador sem leitura anterior não deveria ter delta")
+	}
+
+	want := 2000 / interval.Elapsed.Seconds()
+	if rate := interval.Rate("bytes"); math.Abs(rate-want) > 1e-9 {
+		t.Errorf("taxa inesperada: obtido %f, esperado %f", rate, want)
+	}
+	if rate := interval.Rate("pacotes"); rate != 0 {
+		t.Errorf("contador parado deveria ter taxa 0, obtido %f", rate)
+	}
+	if rate := (Interval{}).Rate("bytes"); rate != 0 {
+		t.Errorf("intervalo vazio deveria ter taxa 0, obtido %f", rate)
+	}
+}
+
+func TestWarmSleepsOnce(t *testing.T) {
+	samplers := make([]Sampler, 4)
+	read := func() (Snapshot, error) { return Snapshot{"x": 1}, nil }
+
+	start := time.Now()
+	Warm(func() {
+		for i := range samplers {
+			samplers[i].Sample(read)
+		}
+	})
+	if elapsed := time.Since(start); elapsed >= 2*Warmup {
+		t.Errorf("esperado uma única espera de %v, obtido %v", Warmup, elapsed)
+	}
+
+	for i := range samplers {
+		if _, err := samplers[i].Sample(read); err != nil {
+			t.Errorf("sampler %d: %v", i, err)
+		}
+	}
+
+	// Sem nenhum sampler novo, Warm não espera.
+	start = time.Now()
+	Warm(func() { samplers[0].Sample(read) })
+	if elapsed := time.Since(start); elapsed >= Warmup {
+		t.Errorf("Warm sem primeira leitura não deveria esperar, obtido %v", elapsed)
+	}
+}
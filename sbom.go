@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log"
+
+	"monitoramento/sbom"
+)
+
+type sbomOptions struct {
+	formats []string
+	dir     string
+	report  bool
+}
+
+func newSBOMOptions(config map[string]string) sbomOptions {
+	return sbomOptions{
+		formats: splitList(config["sbom_formats"]),
+		dir:     config["sbom_dir"],
+		report:  config["sbom_report"] == "true",
+	}
+}
+
+// exportSBOM gera o SBOM do ciclo e, conforme a configuração, grava os
+// arquivos em sbom_dir e/ou anexa os documentos ao relatório.
+func (a *agent) exportSBOM(info *SystemInfo) {
+	opts := a.sbomOptions
+	if len(opts.formats) == 0 || (opts.dir == "" && !opts.report) {
+		return
+	}
+
+	report, err := sbom.Generate(info.Software, opts.formats, info.Timestamp)
+	if err != nil {
+		log.Printf("Erro ao gerar SBOM: %v", err)
+		return
+	}
+
+	if opts.dir != "" {
+		if err := report.WriteFiles(opts.dir, info.Software.OS.Hostname); err != nil {
+			log.Printf("Erro ao gravar SBOM: %v", err)
+		}
+	}
+	if opts.report {
+		info.SBOM = &report
+	}
+}
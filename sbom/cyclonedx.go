@@ -0,0 +1,100 @@
+package sbom
+
+import (
+	"fmt"
+	"time"
+
+	"monitoramento/software"
+)
+
+// CycloneDX é o subconjunto do formato CycloneDX 1.5 (JSON) gerado aqui.
+type CycloneDX struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     CycloneDXMetadata    `json:"metadata"`
+	Components   []CycloneDXComponent `json:"components"`
+}
+
+type CycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     CycloneDXTools     `json:"tools"`
+	Component CycloneDXComponent `json:"component"`
+}
+
+type CycloneDXTools struct {
+	Components []CycloneDXComponent `json:"components"`
+}
+
+type CycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Properties []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NewCycloneDX descreve a máquina (hostname) como componente principal e
+// cada item do inventário como componente do documento.
+func NewCycloneDX(info software.Info, now time.Time) *CycloneDX {
+	doc := &CycloneDX{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: CycloneDXMetadata{
+			Timestamp: timestamp(now),
+			Tools: CycloneDXTools{
+				Components: []CycloneDXComponent{{Type: "application", Name: toolName}},
+			},
+			Component: CycloneDXComponent{
+				Type:   "device",
+				BOMRef: "host",
+				Name:   firstNonEmpty(info.OS.Hostname, "desconhecido"),
+			},
+		},
+		Components: []CycloneDXComponent{},
+	}
+
+	// O mesmo pacote pode aparecer em vários lugares (um módulo Go em
+	// vários binários, por exemplo), mas o bom-ref precisa ser único.
+	refs := make(map[string]bool)
+	for i, c := range components(info) {
+		ref := firstNonEmpty(c.purl, fmt.Sprintf("component-%d", i))
+		if refs[ref] {
+			ref = fmt.Sprintf("%s#%d", ref, i)
+		}
+		refs[ref] = true
+
+		component := CycloneDXComponent{
+			Type:    c.kind,
+			BOMRef:  ref,
+			Name:    c.name,
+			Version: c.version,
+			PURL:    c.purl,
+		}
+		// O CycloneDX não tem tipo próprio para o kernel.
+		if c.kind == "kernel" {
+			component.Type = "operating-system"
+		}
+		if c.manager != "" {
+			component.Properties = append(component.Properties, CycloneDXProperty{Name: toolName + ":manager", Value: c.manager})
+		}
+		if c.arch != "" {
+			component.Properties = append(component.Properties, CycloneDXProperty{Name: toolName + ":architecture", Value: c.arch})
+		}
+		if c.location != "" {
+			component.Properties = append(component.Properties, CycloneDXProperty{Name: toolName + ":location", Value: c.location})
+		}
+		doc.Components = append(doc.Components, component)
+	}
+
+	return doc
+}
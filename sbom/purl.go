@@ -0,0 +1,117 @@
+package sbom
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"monitoramento/software"
+)
+
+// packagePURL monta o package URL (https://github.com/package-url/purl-spec)
+// de um pacote do sistema. O namespace é a distribuição e o qualificador
+// distro leva a versão dela, como pedem os tipos deb, rpm e apk. Snap,
+// flatpak e Windows não têm tipo de purl e ficam sem.
+func packagePURL(os software.OSInfo, app software.InstalledApp) string {
+	var kind string
+	switch app.Manager {
+	case "deb", "rpm", "apk":
+		kind = app.Manager
+	case "pacman":
+		kind = "alpm"
+	default:
+		return ""
+	}
+
+	namespace := strings.ToLower(os.Name)
+	version := app.Version
+	qualifiers := map[string]string{"arch": app.Architecture}
+	if os.Name != "" && os.Version != "" {
+		qualifiers["distro"] = namespace + "-" + os.Version
+	}
+	// No tipo rpm o epoch vai no qualificador, não na versão.
+	if kind == "rpm" {
+		if epoch, rest, ok := strings.Cut(version, ":"); ok {
+			version = rest
+			qualifiers["epoch"] = epoch
+		}
+	}
+	return purl(kind, namespace, app.Name, version, qualifiers)
+}
+
+// ecosystemPURL cobre os pacotes de linguagem: npm com escopo e Maven
+// ("groupId:artifactId") viram namespace + nome.
+func ecosystemPURL(pkg software.EcosystemPackage) string {
+	switch pkg.Ecosystem {
+	case "pypi":
+		name := strings.ToLower(strings.ReplaceAll(pkg.Name, "_", "-"))
+		return purl("pypi", "", name, pkg.Version, nil)
+	case "npm":
+		if scope, name, ok := strings.Cut(pkg.Name, "/"); ok && strings.HasPrefix(scope, "@") {
+			return purl("npm", scope, name, pkg.Version, nil)
+		}
+		return purl("npm", "", pkg.Name, pkg.Version, nil)
+	case "go":
+		if pkg.Name == "stdlib" {
+			return purl("golang", "", "stdlib", goVersion(pkg.Version), nil)
+		}
+		namespace, name := "", pkg.Name
+		if i := strings.LastIndex(pkg.Name, "/"); i >= 0 {
+			namespace, name = pkg.Name[:i], pkg.Name[i+1:]
+		}
+		return purl("golang", namespace, name, pkg.Version, nil)
+	case "maven":
+		if group, artifact, ok := strings.Cut(pkg.Name, ":"); ok {
+			return purl("maven", group, artifact, pkg.Version, nil)
+		}
+	}
+	return ""
+}
+
+// goVersion converte a versão do toolchain gravada no binário ("go1.22.1" ou
+// "go1.22.1 X:boringcrypto") na versão usada pelo purl ("1.22.1").
+func goVersion(version string) string {
+	fields := strings.Fields(version)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(fields[0], "go")
+}
+
+func purl(kind, namespace, name, version string, qualifiers map[string]string) string {
+	var b strings.Builder
+	b.WriteString("pkg:" + kind + "/")
+	if namespace != "" {
+		for _, segment := range strings.Split(namespace, "/") {
+			b.WriteString(escape(segment) + "/")
+		}
+	}
+	b.WriteString(escape(name))
+	if version != "" {
+		b.WriteString("@" + escape(version))
+	}
+
+	var keys []string
+	for key, value := range qualifiers {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		if i == 0 {
+			b.WriteString("?")
+		} else {
+			b.WriteString("&")
+		}
+		b.WriteString(key + "=" + escape(qualifiers[key]))
+	}
+
+	return b.String()
+}
+
+// escape codifica tudo que não é seguro no purl; ":" vira "%3A" (epoch do
+// dpkg) e "+" vira "%2B".
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
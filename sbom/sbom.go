@@ -0,0 +1,150 @@
+// Package sbom gera a lista de materiais de software (SBOM) da máquina nos
+// formatos CycloneDX e SPDX, a partir do que o pacote software coletou.
+package sbom
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"monitoramento/software"
+)
+
+const toolName = "monitoramento"
+
+// Report é a seção do relatório com os documentos pedidos na configuração.
+type Report struct {
+	CycloneDX *CycloneDX `json:"cyclonedx,omitempty"`
+	SPDX      *SPDX      `json:"spdx,omitempty"`
+}
+
+// component é a visão comum aos dois formatos de cada item do inventário.
+type component struct {
+	kind     string // "operating-system", "kernel", "application" ou "library"
+	name     string
+	version  string
+	arch     string
+	manager  string
+	purl     string
+	location string
+}
+
+// components monta a lista na ordem do documento: sistema operacional,
+// kernel, pacotes do sistema e pacotes dos ecossistemas de linguagem.
+func components(info software.Info) []component {
+	list := []component{{
+		kind:    "operating-system",
+		name:    firstNonEmpty(info.OS.Name, "desconhecido"),
+		version: info.OS.Version,
+		arch:    info.OS.Architecture,
+	}}
+	if info.Kernel != "" {
+		list = append(list, component{kind: "kernel", name: "kernel", version: info.Kernel, arch: info.OS.Architecture})
+	}
+
+	for _, app := range info.InstalledApps {
+		list = append(list, component{
+			kind:    "application",
+			name:    app.Name,
+			version: app.Version,
+			arch:    app.Architecture,
+			manager: app.Manager,
+			purl:    packagePURL(info.OS, app),
+		})
+	}
+
+	for _, pkg := range info.EcosystemPackages {
+		list = append(list, component{
+			kind:     "library",
+			name:     pkg.Name,
+			version:  pkg.Version,
+			manager:  pkg.Ecosystem,
+			purl:     ecosystemPURL(pkg),
+			location: pkg.Location,
+		})
+	}
+
+	return list
+}
+
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+const (
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+)
+
+// Generate gera os documentos nos formatos pedidos.
+func Generate(info software.Info, formats []string, now time.Time) (Report, error) {
+	var report Report
+	for _, format := range formats {
+		switch strings.ToLower(format) {
+		case FormatCycloneDX:
+			report.CycloneDX = NewCycloneDX(info, now)
+		case FormatSPDX:
+			report.SPDX = NewSPDX(info, now)
+		default:
+			return Report{}, fmt.Errorf("formato de SBOM desconhecido: %s", format)
+		}
+	}
+	return report, nil
+}
+
+// WriteFiles grava cada documento do relatório em dir como
+// <nome>.cdx.json e <nome>.spdx.json, substituindo os anteriores.
+func (r Report) WriteFiles(dir, name string) error {
+	files := map[string]any{}
+	if r.CycloneDX != nil {
+		files[name+".cdx.json"] = r.CycloneDX
+	}
+	if r.SPDX != nil {
+		files[name+".spdx.json"] = r.SPDX
+	}
+
+	for file, doc := range files {
+		data, err := Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("erro ao gerar %s: %v", file, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), data, 0644); err != nil {
+			return fmt.Errorf("erro ao gravar %s: %v", file, err)
+		}
+	}
+	return nil
+}
+
+// Marshal serializa um documento indentado e sem escapar "&" nos purls.
+func Marshal(doc any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,88 @@
+package sbom
+
+import (
+	"testing"
+	"time"
+
+	"monitoramento/software"
+)
+
+func TestPackagePURL(t *testing.T) {
+	tests := []struct {
+		os   software.OSInfo
+		app  software.InstalledApp
+		want string
+	}{
+		{
+			software.OSInfo{Name: "Debian", Version: "12"},
+			software.InstalledApp{Name: "libc6", Version: "2.36-9+deb12u4", Architecture: "amd64", Manager: "deb"},
+			"pkg:deb/debian/libc6@2.36-9%2Bdeb12u4?arch=amd64&distro=debian-12",
+		},
+		{
+			software.OSInfo{Name: "Fedora", Version: "40"},
+			software.InstalledApp{Name: "openssl", Version: "1:3.2.2-3.fc40", Architecture: "x86_64", Manager: "rpm"},
+			"pkg:rpm/fedora/openssl@3.2.2-3.fc40?arch=x86_64&distro=fedora-40&epoch=1",
+		},
+		{
+			software.OSInfo{Name: "Fedora", Version: "40"},
+			software.InstalledApp{Name: "bash", Version: "5.2.26-3.fc40", Architecture: "x86_64", Manager: "rpm"},
+			"pkg:rpm/fedora/bash@5.2.26-3.fc40?arch=x86_64&distro=fedora-40",
+		},
+		{
+			software.OSInfo{Name: "Ubuntu", Version: "24.04"},
+			software.InstalledApp{Name: "firefox", Version: "128.0", Manager: "snap"},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := packagePURL(tt.os, tt.app); got != tt.want {
+			t.Errorf("packagePURL(%s %s) = %q, esperado %q", tt.app.Name, tt.app.Version, got, tt.want)
+		}
+	}
+}
+
+func TestEcosystemPURL(t *testing.T) {
+	tests := []struct {
+		pkg  software.EcosystemPackage
+		want string
+	}{
+		{software.EcosystemPackage{Ecosystem: "go", Name: "stdlib", Version: "go1.22.1"}, "pkg:golang/stdlib@1.22.1"},
+		{software.EcosystemPackage{Ecosystem: "go", Name: "stdlib", Version: "go1.21.5 X:boringcrypto"}, "pkg:golang/stdlib@1.21.5"},
+		{software.EcosystemPackage{Ecosystem: "go", Name: "golang.org/x/net", Version: "v0.23.0"}, "pkg:golang/golang.org/x/net@v0.23.0"},
+		{software.EcosystemPackage{Ecosystem: "npm", Name: "@babel/core", Version: "7.24.0"}, "pkg:npm/%40babel/core@7.24.0"},
+		{software.EcosystemPackage{Ecosystem: "pypi", Name: "Django_Rest", Version: "3.15"}, "pkg:pypi/django-rest@3.15"},
+		{software.EcosystemPackage{Ecosystem: "maven", Name: "org.yaml:snakeyaml", Version: "2.2"}, "pkg:maven/org.yaml/snakeyaml@2.2"},
+	}
+
+	for _, tt := range tests {
+		if got := ecosystemPURL(tt.pkg); got != tt.want {
+			t.Errorf("ecosystemPURL(%s %s) = %q, esperado %q", tt.pkg.Name, tt.pkg.Version, got, tt.want)
+		}
+	}
+}
+
+func TestCycloneDXUniqueBOMRefs(t *testing.T) {
+	dep := software.EcosystemPackage{Ecosystem: "go", Name: "golang.org/x/net", Version: "v0.23.0"}
+	info := software.Info{OS: software.OSInfo{Hostname: "srv01", Name: "Debian", Version: "12"}}
+	for _, location := range []string{"/usr/local/bin/a", "/usr/local/bin/b"} {
+		dep.Location = location
+		info.EcosystemPackages = append(info.EcosystemPackages, dep)
+	}
+
+	doc := NewCycloneDX(info, time.Now())
+
+	refs := make(map[string]bool)
+	for _, c := range doc.Components {
+		if refs[c.BOMRef] {
+			t.Errorf("bom-ref duplicado: %s", c.BOMRef)
+		}
+		refs[c.BOMRef] = true
+	}
+	if len(doc.Components) != 3 {
+		t.Fatalf("esperado 3 componentes, obtido %d", len(doc.Components))
+	}
+	if last := doc.Components[2]; last.PURL != "pkg:golang/golang.org/x/net@v0.23.0" {
+		t.Errorf("purl deveria continuar igual, obtido %q", last.PURL)
+	}
+}
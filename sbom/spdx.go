@@ -0,0 +1,120 @@
+package sbom
+
+import (
+	"fmt"
+	"time"
+
+	"monitoramento/software"
+)
+
+// SPDX é o subconjunto do formato SPDX 2.3 (JSON) gerado aqui. Como o
+// inventário não conhece licenças nem origem dos pacotes, esses campos vão
+// como NOASSERTION.
+type SPDX struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo   `json:"creationInfo"`
+	Packages          []SPDXPackage      `json:"packages"`
+	Relationships     []SPDXRelationship `json:"relationships"`
+}
+
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type SPDXPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs          []SPDXExternalRef `json:"externalRefs,omitempty"`
+}
+
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type SPDXRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+const noAssertion = "NOASSERTION"
+
+// NewSPDX gera um pacote por item do inventário; o documento descreve o
+// sistema operacional, que contém os demais pacotes.
+func NewSPDX(info software.Info, now time.Time) *SPDX {
+	hostname := firstNonEmpty(info.OS.Hostname, "desconhecido")
+	doc := &SPDX{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              hostname,
+		DocumentNamespace: fmt.Sprintf("https://%s/spdx/%s-%s", toolName, hostname, newUUID()),
+		CreationInfo: SPDXCreationInfo{
+			Created:  timestamp(now),
+			Creators: []string{"Tool: " + toolName},
+		},
+	}
+
+	var osID string
+	for i, c := range components(info) {
+		pkg := SPDXPackage{
+			Name:             c.name,
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i),
+			VersionInfo:      c.version,
+			DownloadLocation: noAssertion,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  noAssertion,
+			CopyrightText:    noAssertion,
+		}
+
+		switch c.kind {
+		case "operating-system", "kernel":
+			pkg.PrimaryPackagePurpose = "OPERATING-SYSTEM"
+		case "application":
+			pkg.PrimaryPackagePurpose = "APPLICATION"
+		default:
+			pkg.PrimaryPackagePurpose = "LIBRARY"
+		}
+
+		if c.purl != "" {
+			pkg.ExternalRefs = []SPDXExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  c.purl,
+			}}
+		}
+
+		if c.kind == "operating-system" {
+			osID = pkg.SPDXID
+			doc.Relationships = append(doc.Relationships, SPDXRelationship{
+				SPDXElementID:      doc.SPDXID,
+				RelationshipType:   "DESCRIBES",
+				RelatedSPDXElement: osID,
+			})
+		} else {
+			doc.Relationships = append(doc.Relationships, SPDXRelationship{
+				SPDXElementID:      osID,
+				RelationshipType:   "CONTAINS",
+				RelatedSPDXElement: pkg.SPDXID,
+			})
+		}
+
+		doc.Packages = append(doc.Packages, pkg)
+	}
+
+	return doc
+}
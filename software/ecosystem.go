@@ -0,0 +1,427 @@
+package software
+
+import (
+	"archive/zip"
+	"bufio"
+	"debug/buildinfo"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EcosystemOptions liga a busca por pacotes de linguagens, que não aparecem
+// no gerenciador do sistema. Os caminhos extras somam aos padrões de cada
+// ecossistema; Go e JAR só são procurados nos caminhos informados.
+type EcosystemOptions struct {
+	Python      bool
+	PythonPaths []string
+	NPM         bool
+	NPMPaths    []string
+	GoPaths     []string
+	JARPaths    []string
+}
+
+type EcosystemPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Location  string `json:"location"`
+}
+
+var defaultPythonPaths = []string{
+	"/usr/lib/python3/dist-packages",
+	"/usr/lib/python3*/site-packages",
+	"/usr/lib64/python3*/site-packages",
+	"/usr/local/lib/python3*/dist-packages",
+	"/usr/local/lib/python3*/site-packages",
+}
+
+var defaultNPMPaths = []string{
+	"/usr/lib/node_modules",
+	"/usr/local/lib/node_modules",
+}
+
+func getEcosystemPackages(opts EcosystemOptions) []EcosystemPackage {
+	var packages []EcosystemPackage
+
+	if opts.Python {
+		for _, dir := range expandPaths(append(defaultPythonPaths, opts.PythonPaths...)) {
+			packages = append(packages, readPythonPackages(dir)...)
+		}
+	}
+	if opts.NPM {
+		for _, dir := range expandPaths(append(defaultNPMPaths, opts.NPMPaths...)) {
+			packages = append(packages, readNPMPackages(dir)...)
+		}
+	}
+	for _, dir := range expandPaths(opts.GoPaths) {
+		packages = append(packages, readGoBinaries(dir)...)
+	}
+	for _, dir := range expandPaths(opts.JARPaths) {
+		packages = append(packages, readJARs(dir)...)
+	}
+	goCache.sweep()
+	jarCache.sweep()
+
+	return packages
+}
+
+// goCache e jarCache guardam o que foi lido de cada binário Go e JAR, já
+// que abrir todos os executáveis de um diretório a cada ciclo é caro e eles
+// quase nunca mudam. Arquivos que não são Go também entram, com resultado
+// vazio. Cada varredura tem o seu cache: um JAR executável passa pelas duas
+// e o resultado de uma não pode aparecer na outra.
+var (
+	goCache  = newScanCache()
+	jarCache = newScanCache()
+)
+
+type scanCache struct {
+	mu      sync.Mutex
+	entries map[string]scanEntry
+	seen    map[string]scanEntry
+}
+
+func newScanCache() *scanCache {
+	return &scanCache{entries: make(map[string]scanEntry), seen: make(map[string]scanEntry)}
+}
+
+type scanEntry struct {
+	modTime  time.Time
+	size     int64
+	packages []EcosystemPackage
+}
+
+// get devolve o resultado guardado para path se o arquivo não mudou (mesma
+// data de modificação e tamanho) desde a última leitura; senão chama read.
+func (c *scanCache) get(path string, info fs.FileInfo, read func(string) []EcosystemPackage) []EcosystemPackage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[path]
+	if !ok || !entry.modTime.Equal(info.ModTime()) || entry.size != info.Size() {
+		entry = scanEntry{modTime: info.ModTime(), size: info.Size(), packages: read(path)}
+	}
+	c.seen[path] = entry
+	return entry.packages
+}
+
+// sweep fecha uma varredura: só os arquivos vistos nela continuam guardados.
+func (c *scanCache) sweep() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries, c.seen = c.seen, make(map[string]scanEntry)
+}
+
+// expandPaths resolve os curingas e remove duplicados, para não listar duas
+// vezes o mesmo diretório vindo do padrão e da configuração.
+func expandPaths(patterns []string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// readPythonPackages lê o METADATA dos *.dist-info (wheel) e o PKG-INFO dos
+// *.egg-info (setuptools, que pode ser arquivo ou diretório).
+func readPythonPackages(dir string) []EcosystemPackage {
+	var packages []EcosystemPackage
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		var metadata string
+		switch {
+		case strings.HasSuffix(entry.Name(), ".dist-info"):
+			metadata = filepath.Join(path, "METADATA")
+		case strings.HasSuffix(entry.Name(), ".egg-info") && entry.IsDir():
+			metadata = filepath.Join(path, "PKG-INFO")
+		case strings.HasSuffix(entry.Name(), ".egg-info"):
+			metadata = path
+		default:
+			continue
+		}
+
+		headers := readHeaders(metadata)
+		if headers["Name"] == "" {
+			continue
+		}
+		packages = append(packages, EcosystemPackage{
+			Ecosystem: "pypi",
+			Name:      headers["Name"],
+			Version:   headers["Version"],
+			Location:  path,
+		})
+	}
+
+	return packages
+}
+
+// readHeaders lê cabeçalhos "Chave: valor" até a primeira linha em branco,
+// formato usado tanto pelo METADATA do Python quanto pelo MANIFEST.MF.
+func readHeaders(path string) map[string]string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	return parseHeaders(file)
+}
+
+// parseHeaders junta as linhas de continuação, que começam com espaço, ao
+// cabeçalho anterior. O MANIFEST.MF quebra as linhas em 72 bytes, muitas
+// vezes no meio do valor, então o espaço inicial é descartado e o resto
+// emendado direto.
+func parseHeaders(r io.Reader) map[string]string {
+	headers := make(map[string]string)
+	// last fica vazio quando o cabeçalho anterior foi ignorado (repetido).
+	var last string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if last != "" {
+				headers[last] += line[1:]
+			}
+			continue
+		}
+
+		last = ""
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			if _, exists := headers[key]; !exists {
+				headers[key] = strings.TrimPrefix(value, " ")
+				last = key
+			}
+		}
+	}
+
+	for key, value := range headers {
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// readNPMPackages percorre um node_modules global, incluindo pacotes com
+// escopo (@escopo/nome) e as dependências aninhadas em node_modules internos.
+func readNPMPackages(dir string) []EcosystemPackage {
+	var packages []EcosystemPackage
+
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		// O WalkDir não segue links simbólicos, então pacotes instalados com
+		// "npm link" ficam de fora.
+		if err != nil || d.IsDir() || d.Name() != "package.json" {
+			return nil
+		}
+
+		// Só interessa o package.json da raiz de cada pacote, cujo diretório
+		// fica logo abaixo de node_modules (ou de node_modules/@escopo).
+		parent := filepath.Dir(filepath.Dir(path))
+		if strings.HasPrefix(filepath.Base(parent), "@") {
+			parent = filepath.Dir(parent)
+		}
+		if filepath.Base(parent) != "node_modules" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var manifest struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		}
+		if json.Unmarshal(data, &manifest) != nil || manifest.Name == "" {
+			return nil
+		}
+
+		packages = append(packages, EcosystemPackage{
+			Ecosystem: "npm",
+			Name:      manifest.Name,
+			Version:   manifest.Version,
+			Location:  filepath.Dir(path),
+		})
+		return nil
+	})
+
+	return packages
+}
+
+// readGoBinaries procura executáveis Go e lê o build info embutido: o módulo
+// principal e cada dependência compilada no binário.
+func readGoBinaries(dir string) []EcosystemPackage {
+	var packages []EcosystemPackage
+
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Mode().Perm()&0111 == 0 && !strings.HasSuffix(path, ".exe") {
+			return nil
+		}
+		packages = append(packages, goCache.get(path, info, readGoBinary)...)
+		return nil
+	})
+
+	return packages
+}
+
+func readGoBinary(path string) []EcosystemPackage {
+	build, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var packages []EcosystemPackage
+	if build.Main.Path != "" {
+		packages = append(packages, EcosystemPackage{
+			Ecosystem: "go",
+			Name:      build.Main.Path,
+			Version:   build.Main.Version,
+			Location:  path,
+		})
+	}
+	packages = append(packages, EcosystemPackage{
+		Ecosystem: "go",
+		Name:      "stdlib",
+		Version:   build.GoVersion,
+		Location:  path,
+	})
+	for _, dep := range build.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		packages = append(packages, EcosystemPackage{
+			Ecosystem: "go",
+			Name:      dep.Path,
+			Version:   dep.Version,
+			Location:  path,
+		})
+	}
+	return packages
+}
+
+// readJARs lê os pom.properties que o Maven grava dentro do JAR (groupId,
+// artifactId e versão, o que dá para casar com avisos de segurança) e, se não
+// houver nenhum, o META-INF/MANIFEST.MF.
+func readJARs(dir string) []EcosystemPackage {
+	var packages []EcosystemPackage
+
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".jar" && ext != ".war" && ext != ".ear" {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			packages = append(packages, jarCache.get(path, info, readJAR)...)
+		}
+		return nil
+	})
+
+	return packages
+}
+
+func readJAR(path string) []EcosystemPackage {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil
+	}
+	defer archive.Close()
+
+	var packages []EcosystemPackage
+	var manifest map[string]string
+
+	for _, file := range archive.File {
+		switch {
+		case strings.HasPrefix(file.Name, "META-INF/maven/") && strings.HasSuffix(file.Name, "/pom.properties"):
+			props := readZipProperties(file)
+			if props["artifactId"] == "" {
+				continue
+			}
+			packages = append(packages, EcosystemPackage{
+				Ecosystem: "maven",
+				Name:      props["groupId"] + ":" + props["artifactId"],
+				Version:   props["version"],
+				Location:  path,
+			})
+		case file.Name == "META-INF/MANIFEST.MF":
+			if rc, err := file.Open(); err == nil {
+				manifest = parseHeaders(rc)
+				rc.Close()
+			}
+		}
+	}
+
+	if len(packages) == 0 && manifest != nil {
+		name := firstNonEmpty(manifest["Bundle-SymbolicName"], manifest["Implementation-Title"], manifest["Specification-Title"])
+		if name != "" {
+			// Bundle-SymbolicName pode vir com diretivas: "nome;singleton:=true".
+			name, _, _ = strings.Cut(name, ";")
+			packages = append(packages, EcosystemPackage{
+				Ecosystem: "maven",
+				Name:      name,
+				Version:   firstNonEmpty(manifest["Bundle-Version"], manifest["Implementation-Version"], manifest["Specification-Version"]),
+				Location:  path,
+			})
+		}
+	}
+
+	return packages
+}
+
+func readZipProperties(file *zip.File) map[string]string {
+	props := make(map[string]string)
+
+	rc, err := file.Open()
+	if err != nil {
+		return props
+	}
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			props[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	return props
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package software
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHeadersContinuation(t *testing.T) {
+	// Linhas de até 72 bytes, quebradas no meio do valor como o jar faz.
+	manifest := "Manifest-Version: 1.0\r\n" +
+		"Bundle-SymbolicName: org.eclipse.equinox.launcher.gtk.linux.x86_6\r\n" +
+		" 4;singleton:=true\r\n" +
+		"Bundle-Version: 1.2.800.v2023\r\n" +
+		" 0227-1500\r\n" +
+		"Manifest-Version: 2.0\r\n" +
+		" ignorado\r\n" +
+		"\r\n" +
+		"Name: org/eclipse/\r\n"
+
+	headers := parseHeaders(strings.NewReader(manifest))
+
+	want := map[string]string{
+		"Manifest-Version":    "1.0",
+		"Bundle-SymbolicName": "org.eclipse.equinox.launcher.gtk.linux.x86_64;singleton:=true",
+		"Bundle-Version":      "1.2.800.v20230227-1500",
+	}
+	for key, value := range want {
+		if headers[key] != value {
+			t.Errorf("%s = %q, esperado %q", key, headers[key], value)
+		}
+	}
+	if len(headers) != len(want) {
+		t.Errorf("cabeçalhos inesperados: %v", headers)
+	}
+}
+
+func writeJAR(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	w := zip.NewWriter(file)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadJARsCache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "launcher.jar")
+	writeJAR(t, path, map[string]string{
+		"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\r\nBundle-SymbolicName: org.eclipse.equinox.launche\r\n r;singleton:=true\r\nBundle-Version: 1.6.400\r\n\r\n",
+	})
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	packages := readJARs(dir)
+	jarCache.sweep()
+	if len(packages) != 1 || packages[0].Name != "org.eclipse.equinox.launcher" || packages[0].Version != "1.6.400" {
+		t.Fatalf("pacotes inesperados: %+v", packages)
+	}
+
+	// Mesmo tamanho e data: o conteúdo não é lido de novo.
+	info, _ := os.Stat(path)
+	if err := os.WriteFile(path, make([]byte, info.Size()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if packages := readJARs(dir); len(packages) != 1 {
+		t.Errorf("esperado resultado do cache, obtido %+v", packages)
+	}
+	jarCache.sweep()
+
+	// Data diferente: relê e o arquivo, agora inválido, não gera pacotes.
+	if err := os.Chtimes(path, time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if packages := readJARs(dir); len(packages) != 0 {
+		t.Errorf("esperado releitura do arquivo alterado, obtido %+v", packages)
+	}
+	jarCache.sweep()
+
+	os.Remove(path)
+	readJARs(dir)
+	jarCache.sweep()
+	if _, ok := jarCache.entries[path]; ok {
+		t.Error("arquivo removido deveria sair do cache")
+	}
+}
+
+func TestEcosystemCacheSharedDir(t *testing.T) {
+	// Um JAR executável está tanto no caminho de binários Go quanto no de
+	// JARs e passa pelas duas varreduras.
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.jar")
+	writeJAR(t, path, map[string]string{
+		"META-INF/maven/org.yaml/snakeyaml/pom.properties": "groupId=org.yaml\nartifactId=snakeyaml\nversion=2.2\n",
+	})
+	if err := os.Chmod(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := EcosystemOptions{GoPaths: []string{dir}, JARPaths: []string{dir}}
+	for cycle := 1; cycle <= 2; cycle++ {
+		packages := getEcosystemPackages(opts)
+		if len(packages) != 1 || packages[0].Ecosystem != "maven" || packages[0].Name != "org.yaml:snakeyaml" {
+			t.Errorf("ciclo %d: esperado o JAR uma única vez, obtido %+v", cycle, packages)
+		}
+	}
+}
+
+func TestReadGoBinary(t *testing.T) {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	var stdlib string
+	for _, pkg := range readGoBinary(executable) {
+		if pkg.Name == "stdlib" {
+			stdlib = pkg.Version
+		}
+	}
+	if stdlib != runtime.Version() {
+		t.Errorf("esperado stdlib %s, obtido %q", runtime.Version(), stdlib)
+	}
+
+	if packages := readGoBinary(filepath.Join(t.TempDir(), "inexistente")); packages != nil {
+		t.Errorf("esperado nenhum pacote, obtido %+v", packages)
+	}
+}
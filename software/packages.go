@@ -0,0 +1,96 @@
+package software
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+type InstalledApp struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	Architecture  string `json:"architecture"`
+	InstallDate   string `json:"install_date"`
+	Source        string `json:"source"`
+	SourcePackage string `json:"source_package,omitempty"`
+	State         string `json:"state"`
+	Manager       string `json:"manager"`
+}
+
+// packageBackend é um gerenciador de pacotes do sistema. Available diz se ele
+// existe na máquina; mais de um pode estar presente ao mesmo tempo (apt com
+// snap e flatpak, por exemplo).
+type packageBackend interface {
+	Name() string
+	Available() bool
+	List() ([]InstalledApp, error)
+}
+
+const defaultRoot = "/"
+
+var packageBackends = []packageBackend{
+	dpkgBackend{root: defaultRoot},
+	rpmBackend{},
+	apkBackend{root: defaultRoot},
+	pacmanBackend{root: defaultRoot},
+	snapBackend{},
+	flatpakBackend{},
+}
+
+func getInstalledApps() ([]InstalledApp, error) {
+	if runtime.GOOS == "windows" {
+		return getWindowsApps()
+	}
+
+	var apps []InstalledApp
+	for _, backend := range packageBackends {
+		if !backend.Available() {
+			continue
+		}
+		list, err := backend.List()
+		if err != nil {
+			log.Printf("Erro ao listar pacotes do %s: %v", backend.Name(), err)
+			continue
+		}
+		apps = append(apps, list...)
+	}
+
+	return apps, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func commandExists(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// formatUnixDate converte um timestamp em segundos (como vem do rpm e do
+// pacman) para o formato usado em InstallDate.
+func formatUnixDate(value string) string {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds <= 0 {
+		return ""
+	}
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
+
+func modTimeDate(paths ...string) string {
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			return info.ModTime().UTC().Format(time.RFC3339)
+		}
+	}
+	return ""
+}
+
+func rootPath(root string, parts ...string) string {
+	return filepath.Join(append([]string{root}, parts...)...)
+}
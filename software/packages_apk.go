@@ -0,0 +1,74 @@
+package software
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// apkBackend lê o banco do apk (Alpine) em /lib/apk/db/installed. Cada
+// pacote é um bloco de linhas "X:valor" separado por linha em branco. O
+// banco não guarda a data de instalação ("t" é a data de build), então
+// InstallDate fica vazio.
+type apkBackend struct {
+	root string
+}
+
+func (b apkBackend) Name() string { return "apk" }
+
+func (b apkBackend) Available() bool {
+	return fileExists(rootPath(b.root, "lib/apk/db/installed"))
+}
+
+func (b apkBackend) List() ([]InstalledApp, error) {
+	file, err := os.Open(rootPath(b.root, "lib/apk/db/installed"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseAPKInstalled(file)
+}
+
+func parseAPKInstalled(r io.Reader) ([]InstalledApp, error) {
+	var apps []InstalledApp
+	var app InstalledApp
+
+	flush := func() {
+		if app.Name != "" {
+			app.State = "installed"
+			app.Manager = "apk"
+			apps = append(apps, app)
+		}
+		app = InstalledApp{}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "P":
+			app.Name = value
+		case "V":
+			app.Version = value
+		case "A":
+			app.Architecture = value
+		case "o":
+			app.SourcePackage = value
+		}
+	}
+	flush()
+
+	return apps, scanner.Err()
+}
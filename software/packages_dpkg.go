@@ -0,0 +1,99 @@
+package software
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// dpkgBackend lê o /var/lib/dpkg/status direto, sem depender da saída do
+// "dpkg -l". O status não guarda a data de instalação; usamos a data de
+// modificação da lista de arquivos do pacote em /var/lib/dpkg/info.
+type dpkgBackend struct {
+	root string
+}
+
+func (b dpkgBackend) Name() string { return "dpkg" }
+
+func (b dpkgBackend) Available() bool {
+	return fileExists(rootPath(b.root, "var/lib/dpkg/status"))
+}
+
+func (b dpkgBackend) List() ([]InstalledApp, error) {
+	file, err := os.Open(rootPath(b.root, "var/lib/dpkg/status"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	apps, err := parseDpkgStatus(file)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range apps {
+		info := rootPath(b.root, "var/lib/dpkg/info")
+		apps[i].InstallDate = modTimeDate(
+			info+"/"+apps[i].Name+":"+apps[i].Architecture+".list",
+			info+"/"+apps[i].Name+".list",
+		)
+	}
+
+	return apps, nil
+}
+
+// Estados em que os arquivos do pacote estão no disco. "config-files" (o
+// "rc" do dpkg -l) e "not-installed" ficam de fora.
+var dpkgInstalledStates = map[string]bool{
+	"installed": true, "unpacked": true, "half-configured": true,
+	"half-installed": true, "triggers-awaited": true, "triggers-pending": true,
+}
+
+func parseDpkgStatus(r io.Reader) ([]InstalledApp, error) {
+	var apps []InstalledApp
+	fields := make(map[string]string)
+
+	flush := func() {
+		defer func() { fields = make(map[string]string) }()
+
+		// Status: "<desejado> <erro> <estado>", ex.: "install ok installed".
+		status := strings.Fields(fields["Status"])
+		if fields["Package"] == "" || len(status) != 3 || !dpkgInstalledStates[status[2]] {
+			return
+		}
+
+		// Source pode vir como "nome (versão)" quando difere do binário.
+		source, _, _ := strings.Cut(fields["Source"], " ")
+
+		apps = append(apps, InstalledApp{
+			Name:          fields["Package"],
+			Version:       fields["Version"],
+			Architecture:  fields["Architecture"],
+			Source:        fields["Origin"],
+			SourcePackage: source,
+			State:         status[2],
+			Manager:       "deb",
+		})
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		// Linhas de continuação (Description, Conffiles) começam com espaço.
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+	flush()
+
+	return apps, scanner.Err()
+}
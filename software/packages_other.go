@@ -0,0 +1,9 @@
+//go:build !windows
+
+package software
+
+import "fmt"
+
+func getWindowsApps() ([]InstalledApp, error) {
+	return nil, fmt.Errorf("registro do Windows não disponível neste sistema")
+}
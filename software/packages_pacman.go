@@ -0,0 +1,71 @@
+package software
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// pacmanBackend lê o banco local do pacman: um diretório por pacote em
+// /var/lib/pacman/local com um arquivo "desc" no formato "%CAMPO%\nvalor".
+type pacmanBackend struct {
+	root string
+}
+
+func (b pacmanBackend) Name() string { return "pacman" }
+
+func (b pacmanBackend) Available() bool {
+	return fileExists(rootPath(b.root, "var/lib/pacman/local"))
+}
+
+func (b pacmanBackend) List() ([]InstalledApp, error) {
+	descs, err := filepath.Glob(rootPath(b.root, "var/lib/pacman/local/*/desc"))
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []InstalledApp
+	for _, desc := range descs {
+		data, err := os.ReadFile(desc)
+		if err != nil {
+			continue
+		}
+		if app, ok := parsePacmanDesc(string(data)); ok {
+			apps = append(apps, app)
+		}
+	}
+
+	return apps, nil
+}
+
+func parsePacmanDesc(data string) (InstalledApp, bool) {
+	fields := make(map[string]string)
+
+	var key string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			key = ""
+		case strings.HasPrefix(line, "%") && strings.HasSuffix(line, "%"):
+			key = strings.Trim(line, "%")
+		case key != "" && fields[key] == "":
+			fields[key] = line
+		}
+	}
+
+	if fields["NAME"] == "" {
+		return InstalledApp{}, false
+	}
+
+	return InstalledApp{
+		Name:          fields["NAME"],
+		Version:       fields["VERSION"],
+		Architecture:  fields["ARCH"],
+		InstallDate:   formatUnixDate(fields["INSTALLDATE"]),
+		Source:        fields["PACKAGER"],
+		SourcePackage: fields["BASE"],
+		State:         "installed",
+		Manager:       "pacman",
+	}, true
+}
@@ -0,0 +1,70 @@
+package software
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+type rpmBackend struct{}
+
+func (rpmBackend) Name() string { return "rpm" }
+
+func (rpmBackend) Available() bool { return commandExists("rpm") }
+
+const rpmQueryFormat = "%{NAME}\t%{EPOCH}\t%{VERSION}\t%{RELEASE}\t%{ARCH}\t%{INSTALLTIME}\t%{VENDOR}\t%{SOURCERPM}\n"
+
+func (rpmBackend) List() ([]InstalledApp, error) {
+	output, err := exec.Command("rpm", "-qa", "--queryformat", rpmQueryFormat).Output()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao executar rpm -qa: %v", err)
+	}
+	return parseRPMQuery(string(output)), nil
+}
+
+// parseRPMQuery monta a versão no formato EVR ("epoch:versão-release"),
+// omitindo o epoch quando o pacote não tem ("(none)").
+func parseRPMQuery(output string) []InstalledApp {
+	var apps []InstalledApp
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 8 || fields[0] == "gpg-pubkey" {
+			continue
+		}
+
+		version := fields[2] + "-" + fields[3]
+		if epoch := fields[1]; epoch != "(none)" && epoch != "" && epoch != "0" {
+			version = epoch + ":" + version
+		}
+
+		vendor := fields[6]
+		if vendor == "(none)" {
+			vendor = ""
+		}
+
+		// SOURCERPM tem o formato "nome-versão-release.src.rpm".
+		source := strings.TrimSuffix(fields[7], ".src.rpm")
+		for i := 0; i < 2; i++ {
+			if idx := strings.LastIndex(source, "-"); idx > 0 {
+				source = source[:idx]
+			}
+		}
+		if fields[7] == "(none)" {
+			source = ""
+		}
+
+		apps = append(apps, InstalledApp{
+			Name:          fields[0],
+			Version:       version,
+			Architecture:  fields[4],
+			InstallDate:   formatUnixDate(fields[5]),
+			Source:        vendor,
+			SourcePackage: source,
+			State:         "installed",
+			Manager:       "rpm",
+		})
+	}
+
+	return apps
+}
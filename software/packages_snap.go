@@ -0,0 +1,90 @@
+package software
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+type snapBackend struct{}
+
+func (snapBackend) Name() string { return "snap" }
+
+func (snapBackend) Available() bool { return commandExists("snap") }
+
+func (snapBackend) List() ([]InstalledApp, error) {
+	output, err := exec.Command("snap", "list", "--all", "--unicode=never").Output()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao executar snap list: %v", err)
+	}
+	return parseSnapList(string(output)), nil
+}
+
+// parseSnapList lê a tabela "Name Version Rev Tracking Publisher Notes".
+// Revisões antigas mantidas pelo snap aparecem com "disabled" em Notes. Com
+// --unicode=never o snap marca publicadores verificados com "**" e os
+// "estrela" com "*", em vez de "✓" e "✪".
+func parseSnapList(output string) []InstalledApp {
+	var apps []InstalledApp
+
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 5 {
+			continue
+		}
+
+		state := "active"
+		if len(fields) >= 6 && strings.Contains(fields[5], "disabled") {
+			state = "disabled"
+		}
+
+		apps = append(apps, InstalledApp{
+			Name:    fields[0],
+			Version: fields[1],
+			Source:  strings.TrimRight(fields[4], "*✓✪") + " (" + fields[3] + ")",
+			State:   state,
+			Manager: "snap",
+		})
+	}
+
+	return apps
+}
+
+type flatpakBackend struct{}
+
+func (flatpakBackend) Name() string { return "flatpak" }
+
+func (flatpakBackend) Available() bool { return commandExists("flatpak") }
+
+func (flatpakBackend) List() ([]InstalledApp, error) {
+	output, err := exec.Command("flatpak", "list", "--columns=application,version,arch,origin,installation").Output()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao executar flatpak list: %v", err)
+	}
+	return parseFlatpakList(string(output)), nil
+}
+
+// parseFlatpakList lê a saída separada por tabulação; runtimes sem versão
+// aparecem com a coluna vazia.
+func parseFlatpakList(output string) []InstalledApp {
+	var apps []InstalledApp
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 5 || fields[0] == "" {
+			continue
+		}
+
+		apps = append(apps, InstalledApp{
+			Name:         fields[0],
+			Version:      fields[1],
+			Architecture: fields[2],
+			Source:       fields[3],
+			State:        "installed (" + fields[4] + ")",
+			Manager:      "flatpak",
+		})
+	}
+
+	return apps
+}
@@ -0,0 +1,187 @@
+package software
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDpkgStatus(t *testing.T) {
+	status := `Package: libssl3
+Status: install ok installed
+Priority: optional
+Section: libs
+Installed-Size: 6236
+Maintainer: Debian OpenSSL Team <pkg-openssl-devel@alioth-lists.debian.net>
+Architecture: amd64
+Multi-Arch: same
+Source: openssl (3.0.11-1~deb12u2)
+Version: 3.0.11-1~deb12u2
+Depends: libc6 (>= 2.34)
+Description: Secure Sockets Layer toolkit - shared libraries
+ This package is part of the OpenSSL project's implementation of the SSL
+ and TLS cryptographic protocols for secure communication over the
+ Internet.
+Homepage: https://www.openssl.org/
+
+Package: nano
+Status: deinstall ok config-files
+Priority: important
+Architecture: amd64
+Version: 7.2-1
+Conffiles:
+ /etc/nanorc 7d6a5b3e4b9a4b7e1e0b6f0d5b7e2c1a
+
+Package: ubuntu-keyring
+Status: install ok unpacked
+Architecture: all
+Origin: Ubuntu
+Version: 2023.11.28.1
+`
+
+	apps, err := parseDpkgStatus(strings.NewReader(status))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []InstalledApp{
+		{Name: "libssl3", Version: "3.0.11-1~deb12u2", Architecture: "amd64", SourcePackage: "openssl", State: "installed", Manager: "deb"},
+		{Name: "ubuntu-keyring", Version: "2023.11.28.1", Architecture: "all", Source: "Ubuntu", State: "unpacked", Manager: "deb"},
+	}
+	if !reflect.DeepEqual(apps, want) {
+		t.Errorf("pacotes inesperados:\n obtido  %+v\n esperado %+v", apps, want)
+	}
+}
+
+func TestParseRPMQuery(t *testing.T) {
+	output := "openssl-libs\t1\t3.0.7\t27.el9\tx86_64\t1700000000\tRed Hat, Inc.\topenssl-3.0.7-27.el9.src.rpm\n" +
+		"bash\t(none)\t5.1.8\t9.el9\tx86_64\t1699990000\tRed Hat, Inc.\tbash-5.1.8-9.el9.src.rpm\n" +
+		"gpg-pubkey\t(none)\tfd431d51\t4ae0493b\t(none)\t1699000000\t(none)\t(none)\n" +
+		"my-tool\t0\t1.0\t1\tnoarch\t0\t(none)\t(none)\n" +
+		"linha incompleta\n"
+
+	want := []InstalledApp{
+		{Name: "openssl-libs", Version: "1:3.0.7-27.el9", Architecture: "x86_64", InstallDate: "2023-11-14T22:13:20Z", Source: "Red Hat, Inc.", SourcePackage: "openssl", State: "installed", Manager: "rpm"},
+		{Name: "bash", Version: "5.1.8-9.el9", Architecture: "x86_64", InstallDate: "2023-11-14T19:26:40Z", Source: "Red Hat, Inc.", SourcePackage: "bash", State: "installed", Manager: "rpm"},
+		{Name: "my-tool", Version: "1.0-1", Architecture: "noarch", State: "installed", Manager: "rpm"},
+	}
+	if apps := parseRPMQuery(output); !reflect.DeepEqual(apps, want) {
+		t.Errorf("pacotes inesperados:\n obtido  %+v\n esperado %+v", apps, want)
+	}
+}
+
+func TestParseAPKInstalled(t *testing.T) {
+	installed := `C:Q1W4pP0Xg4dQmQ2vYk7t2l3zv8hXo=
+P:musl
+V:1.2.4_git20230717-r4
+A:x86_64
+S:407447
+I:667648
+T:the musl c library (libc) implementation
+U:https://musl.libc.org/
+L:MIT
+o:musl
+m:Timo Teräs <timo.teras@iki.fi>
+t:1712161335
+c:2a7bf0bdf2d75a7cd1a0a1bab18b1d7a5b7d1c12
+F:lib
+R:ld-musl-x86_64.so.1
+a:0:0:755
+Z:Q1rrd8Y6G8nHb3yVvuH2Jm8g8n2fQ=
+
+P:ssl_client
+V:3.36.1-r15
+A:x86_64
+o:busybox
+t:1708521219
+`
+
+	apps, err := parseAPKInstalled(strings.NewReader(installed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "t" é a data de build, não de instalação, e não vai para InstallDate.
+	want := []InstalledApp{
+		{Name: "musl", Version: "1.2.4_git20230717-r4", Architecture: "x86_64", SourcePackage: "musl", State: "installed", Manager: "apk"},
+		{Name: "ssl_client", Version: "3.36.1-r15", Architecture: "x86_64", SourcePackage: "busybox", State: "installed", Manager: "apk"},
+	}
+	if !reflect.DeepEqual(apps, want) {
+		t.Errorf("pacotes inesperados:\n obtido  %+v\n esperado %+v", apps, want)
+	}
+}
+
+func TestParsePacmanDesc(t *testing.T) {
+	desc := `%NAME%
+linux-firmware
+
+%VERSION%
+20240409.1addd7dc-1
+
+%BASE%
+linux-firmware
+
+%DESC%
+Firmware files for Linux
+
+%ARCH%
+any
+
+%BUILDDATE%
+1712744104
+
+%INSTALLDATE%
+1713000000
+
+%PACKAGER%
+Arch Linux <arch@archlinux.org>
+
+%LICENSE%
+GPL2
+custom
+`
+
+	app, ok := parsePacmanDesc(desc)
+	if !ok {
+		t.Fatal("esperado pacote válido")
+	}
+	want := InstalledApp{
+		Name:          "linux-firmware",
+		Version:       "20240409.1addd7dc-1",
+		Architecture:  "any",
+		InstallDate:   "2024-04-13T09:20:00Z",
+		Source:        "Arch Linux <arch@archlinux.org>",
+		SourcePackage: "linux-firmware",
+		State:         "installed",
+		Manager:       "pacman",
+	}
+	if app != want {
+		t.Errorf("pacote inesperado:\n obtido  %+v\n esperado %+v", app, want)
+	}
+
+	if _, ok := parsePacmanDesc("%VERSION%\n1.0-1\n"); ok {
+		t.Error("desc sem NAME não deveria ser aceito")
+	}
+}
+
+func TestParseSnapList(t *testing.T) {
+	output := `Name      Version          Rev    Tracking          Publisher     Notes
+core22    20240408         1380   latest/stable     canonical**   base
+firefox   125.0.2-1        4173   latest/stable/esr mozilla**     disabled
+firefox   125.0.3-1        4209   latest/stable/esr mozilla**     -
+snapd     2.62             21465  latest/stable     canonical**   snapd
+htop      3.3.0            4291   latest/stable     maxiberta*    -
+`
+
+	apps := parseSnapList(output)
+	if len(apps) != 5 {
+		t.Fatalf("esperado 5 revisões, obtido %d", len(apps))
+	}
+	want := InstalledApp{Name: "firefox", Version: "125.0.2-1", Source: "mozilla (latest/stable/esr)", State: "disabled", Manager: "snap"}
+	if apps[1] != want {
+		t.Errorf("revisão inesperada:\n obtido  %+v\n esperado %+v", apps[1], want)
+	}
+	if apps[2].State != "active" || apps[0].Source != "canonical (latest/stable)" || apps[4].Source != "maxiberta (latest/stable)" {
+		t.Errorf("revisões inesperadas: %+v", apps)
+	}
+}
@@ -0,0 +1,48 @@
+//go:build windows
+
+package software
+
+import (
+	"golang.org/x/sys/windows/registry"
+)
+
+func getWindowsApps() ([]InstalledApp, error) {
+	var apps []InstalledApp
+
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Close()
+
+	subkeys, err := key.ReadSubKeyNames(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subkeyName := range subkeys {
+		subkey, err := registry.OpenKey(key, subkeyName, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+
+		displayName, _, _ := subkey.GetStringValue("DisplayName")
+		displayVersion, _, _ := subkey.GetStringValue("DisplayVersion")
+		installDate, _, _ := subkey.GetStringValue("InstallDate")
+		publisher, _, _ := subkey.GetStringValue("Publisher")
+		subkey.Close()
+
+		if displayName != "" {
+			apps = append(apps, InstalledApp{
+				Name:        displayName,
+				Version:     displayVersion,
+				InstallDate: installDate,
+				Source:      publisher,
+				State:       "installed",
+				Manager:     "windows",
+			})
+		}
+	}
+
+	return apps, nil
+}
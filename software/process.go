@@ -0,0 +1,228 @@
+package software
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"monitoramento/sampler"
+)
+
+type Process struct {
+	Name        string    `json:"name"`
+	PID         int       `json:"pid"`
+	PPID        int       `json:"ppid"`
+	Cmdline     string    `json:"cmdline"`
+	Exe         string    `json:"exe"`
+	User        string    `json:"user"`
+	UID         int       `json:"uid"`
+	StartTime   time.Time `json:"start_time"`
+	State       string    `json:"state"`
+	Threads     int32     `json:"threads"`
+	OpenFiles   int32     `json:"open_files"`
+	Nice        int32     `json:"nice"`
+	Cgroup      string    `json:"cgroup"`
+	ContainerID string    `json:"container_id"`
+	CPUUsage    float64   `json:"cpu_usage_percent"`
+	MemUsage    uint64    `json:"memory_usage_bytes"`
+	ReadBytes   uint64    `json:"read_bytes_per_sec"`
+	WriteBytes  uint64    `json:"write_bytes_per_sec"`
+	Sockets     int       `json:"sockets"`
+	NetSent     uint64    `json:"net_sent_bytes_per_sec"`
+	NetRecv     uint64    `json:"net_recv_bytes_per_sec"`
+	Instances   int       `json:"instances,omitempty"`
+	PIDs        []int     `json:"pids,omitempty"`
+}
+
+var processSampler sampler.Sampler
+
+// Prime faz a primeira leitura dos contadores usados por Collect; ver
+// sampler.Warm.
+func Prime() {
+	getRunningProcesses()
+	getSystemServices()
+}
+
+// getRunningProcesses calcula o uso de CPU de cada processo a partir do tempo
+// de CPU consumido desde a coleta anterior, e não da média desde o início do
+// processo. A chave inclui o horário de criação para não misturar processos
+// diferentes que reaproveitaram o mesmo PID.
+func getRunningProcesses() ([]Process, error) {
+	var runningProcesses []Process
+	var keys []string
+	var traffic socketTraffic
+
+	interval, err := processSampler.Sample(func() (sampler.Snapshot, error) {
+		processes, err := process.Processes()
+		if err != nil {
+			return nil, err
+		}
+
+		runningProcesses = runningProcesses[:0]
+		keys = keys[:0]
+		snapshot := make(sampler.Snapshot)
+
+		for _, p := range processes {
+			name, err := p.Name()
+			if err != nil {
+				continue
+			}
+
+			memInfo, err := p.MemoryInfo()
+			if err != nil {
+				memInfo = &process.MemoryInfoStat{}
+			}
+
+			proc := Process{
+				Name:     name,
+				PID:      int(p.Pid),
+				MemUsage: memInfo.RSS,
+			}
+			fillProcessDetails(p, &proc)
+
+			key := fmt.Sprintf("%d:%d", p.Pid, proc.StartTime.UnixMilli())
+			if times, err := p.Times(); err == nil {
+				snapshot[key+":cpu"] = uint64((times.User + times.System) * 1000)
+			}
+			// No Linux são o read_bytes/write_bytes de /proc/<pid>/io, ou
+			// seja, o que de fato chegou ao dispositivo de bloco.
+			if io, err := p.IOCounters(); err == nil {
+				snapshot[key+":read"] = io.ReadBytes
+				snapshot[key+":write"] = io.WriteBytes
+			}
+
+			runningProcesses = append(runningProcesses, proc)
+			keys = append(keys, key)
+		}
+
+		if runtime.GOOS == "linux" {
+			traffic = readSocketTraffic()
+			for key, value := range traffic {
+				snapshot["socket:"+key] = value
+			}
+		}
+
+		return snapshot, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sent, recv := traffic.perPID(interval, "socket:")
+
+	elapsedMs := float64(interval.Elapsed.Milliseconds())
+	for i := range runningProcesses {
+		proc := &runningProcesses[i]
+		if cpuMs, ok := interval.Delta(keys[i] + ":cpu"); ok && elapsedMs > 0 {
+			proc.CPUUsage = float64(cpuMs) / elapsedMs * 100
+		}
+		proc.ReadBytes = uint64(interval.Rate(keys[i] + ":read"))
+		proc.WriteBytes = uint64(interval.Rate(keys[i] + ":write"))
+		if elapsedMs > 0 {
+			proc.NetSent = uint64(float64(sent[proc.PID]) / elapsedMs * 1000)
+			proc.NetRecv = uint64(float64(recv[proc.PID]) / elapsedMs * 1000)
+		}
+	}
+
+	return runningProcesses, nil
+}
+
+// fillProcessDetails completa o que der para ler do processo. Boa parte
+// desses dados exige permissão sobre o processo, então erros só deixam o
+// campo vazio.
+func fillProcessDetails(p *process.Process, proc *Process) {
+	if ppid, err := p.Ppid(); err == nil {
+		proc.PPID = int(ppid)
+	}
+	proc.Cmdline, _ = p.Cmdline()
+	proc.Exe, _ = p.Exe()
+	proc.User, _ = p.Username()
+	if uids, err := p.Uids(); err == nil && len(uids) > 0 {
+		proc.UID = int(uids[0])
+	}
+	if created, err := p.CreateTime(); err == nil {
+		proc.StartTime = time.UnixMilli(created)
+	}
+	if status, err := p.Status(); err == nil && len(status) > 0 {
+		proc.State = status[0]
+	}
+	proc.Threads, _ = p.NumThreads()
+	proc.OpenFiles, _ = p.NumFDs()
+	proc.Nice, _ = p.Nice()
+
+	if runtime.GOOS == "linux" {
+		// O gopsutil devolve a prioridade do kernel (20 para nice 0) no
+		// Linux; o nice de verdade é o campo 19 de /proc/<pid>/stat.
+		if nice, ok := readProcessNice(filepath.Join("/proc", strconv.Itoa(proc.PID), "stat")); ok {
+			proc.Nice = nice
+		}
+		proc.Sockets = countSockets(proc.PID)
+		proc.Cgroup = readProcessCgroup(filepath.Join("/proc", strconv.Itoa(proc.PID), "cgroup"))
+		proc.ContainerID = containerID(proc.Cgroup)
+	}
+}
+
+func readProcessNice(path string) (int32, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+
+	// O nome do processo (campo 2) pode ter espaços e parênteses, então os
+	// campos são contados a partir do último ")".
+	end := strings.LastIndexByte(string(data), ')')
+	if end < 0 {
+		return 0, false
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 17 {
+		return 0, false
+	}
+
+	nice, err := strconv.ParseInt(fields[16], 10, 32)
+	return int32(nice), err == nil
+}
+
+// readProcessCgroup devolve o caminho do processo na hierarquia v2 (linha
+// "0::/..."); em hosts só com cgroup v1 usa a hierarquia do systemd.
+func readProcessCgroup(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	var v1 string
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" && parts[2] != "/" {
+			return parts[2]
+		}
+		if parts[1] == "name=systemd" {
+			v1 = parts[2]
+		}
+	}
+	return v1
+}
+
+// Docker, containerd, CRI-O e Podman usam o ID de 64 caracteres hexadecimais
+// no caminho do cgroup: "/docker/<id>", "docker-<id>.scope",
+// "cri-containerd-<id>.scope", "crio-<id>.scope", "libpod-<id>.scope".
+var containerIDPattern = regexp.MustCompile(`(?:^|[/-])([0-9a-f]{64})(?:\.scope)?(?:/|$)`)
+
+func containerID(cgroup string) string {
+	matches := containerIDPattern.FindAllStringSubmatch(cgroup, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[len(matches)-1][1]
+}
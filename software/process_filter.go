@@ -0,0 +1,128 @@
+package software
+
+import (
+	"path"
+	"sort"
+)
+
+// ProcessOptions controla quais processos vão para o relatório. Cada Top*
+// maior que zero seleciona os N primeiros por aquele critério e o relatório
+// leva a união das seleções; com todos zerados vão todos os processos.
+// Include e Exclude usam curingas de path.Match sobre o nome do processo.
+type ProcessOptions struct {
+	TopCPU      int
+	TopMemory   int
+	TopIO       int
+	TopFDs      int
+	TopNetwork  int
+	GroupByName bool
+	Include     []string
+	Exclude     []string
+}
+
+func (o ProcessOptions) matches(name string) bool {
+	for _, pattern := range o.Exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+	if len(o.Include) == 0 {
+		return true
+	}
+	for _, pattern := range o.Include {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func selectProcesses(all []Process, opts ProcessOptions) []Process {
+	var processes []Process
+	for _, p := range all {
+		if opts.matches(p.Name) {
+			processes = append(processes, p)
+		}
+	}
+
+	if opts.GroupByName {
+		processes = groupProcessesByName(processes)
+	}
+
+	if opts.TopCPU > 0 || opts.TopMemory > 0 || opts.TopIO > 0 || opts.TopFDs > 0 || opts.TopNetwork > 0 {
+		selected := make(map[int]bool)
+		for _, top := range []struct {
+			n    int
+			less func(a, b Process) bool
+		}{
+			{opts.TopCPU, func(a, b Process) bool { return a.CPUUsage > b.CPUUsage }},
+			{opts.TopMemory, func(a, b Process) bool { return a.MemUsage > b.MemUsage }},
+			{opts.TopIO, func(a, b Process) bool { return a.ReadBytes+a.WriteBytes > b.ReadBytes+b.WriteBytes }},
+			{opts.TopFDs, func(a, b Process) bool { return a.OpenFiles > b.OpenFiles }},
+			{opts.TopNetwork, func(a, b Process) bool { return a.NetSent+a.NetRecv > b.NetSent+b.NetRecv }},
+		} {
+			if top.n <= 0 {
+				continue
+			}
+			order := make([]int, len(processes))
+			for i := range order {
+				order[i] = i
+			}
+			sort.SliceStable(order, func(i, j int) bool { return top.less(processes[order[i]], processes[order[j]]) })
+			for _, i := range order[:min(top.n, len(order))] {
+				selected[i] = true
+			}
+		}
+
+		var filtered []Process
+		for i, p := range processes {
+			if selected[i] {
+				filtered = append(filtered, p)
+			}
+		}
+		processes = filtered
+	}
+
+	sort.SliceStable(processes, func(i, j int) bool { return processes[i].CPUUsage > processes[j].CPUUsage })
+	return processes
+}
+
+// groupProcessesByName junta os processos com o mesmo nome em uma entrada só,
+// somando CPU, memória, threads, descritores, sockets e I/O. Os demais campos vêm do
+// processo mais antigo do grupo.
+func groupProcessesByName(processes []Process) []Process {
+	var grouped []Process
+	index := make(map[string]int)
+
+	for _, p := range processes {
+		i, ok := index[p.Name]
+		if !ok {
+			p.Instances = 1
+			p.PIDs = []int{p.PID}
+			index[p.Name] = len(grouped)
+			grouped = append(grouped, p)
+			continue
+		}
+
+		g := &grouped[i]
+		g.Instances++
+		g.PIDs = append(g.PIDs, p.PID)
+		g.CPUUsage += p.CPUUsage
+		g.MemUsage += p.MemUsage
+		g.Threads += p.Threads
+		g.OpenFiles += p.OpenFiles
+		g.ReadBytes += p.ReadBytes
+		g.WriteBytes += p.WriteBytes
+		g.Sockets += p.Sockets
+		g.NetSent += p.NetSent
+		g.NetRecv += p.NetRecv
+
+		if p.StartTime.Before(g.StartTime) {
+			g.PID, g.PPID, g.Cmdline, g.Exe = p.PID, p.PPID, p.Cmdline, p.Exe
+			g.User, g.UID, g.StartTime, g.State = p.User, p.UID, p.StartTime, p.State
+			g.Nice, g.Cgroup, g.ContainerID = p.Nice, p.Cgroup, p.ContainerID
+		}
+	}
+
+	return grouped
+}
@@ -0,0 +1,133 @@
+package software
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"monitoramento/sampler"
+)
+
+// socketTraffic são os bytes enviados/recebidos acumulados por socket TCP,
+// indexados por "pid:local>remoto:sent" e "pid:local>remoto:recv".
+type socketTraffic map[string]uint64
+
+var ssPIDPattern = regexp.MustCompile(`pid=(\d+)`)
+
+// readSocketTraffic usa o "ss -tinp", que mostra os contadores de bytes de
+// cada conexão TCP e o processo dono. Um socket compartilhado entre vários
+// processos (depois de um fork, por exemplo) conta só para o primeiro.
+// Sem o ss ou sem permissão o resultado fica vazio.
+func readSocketTraffic() socketTraffic {
+	output, err := exec.Command("ss", "-tinpH").Output()
+	if err != nil {
+		return nil
+	}
+	return parseSS(output)
+}
+
+func parseSS(output []byte) socketTraffic {
+	traffic := make(socketTraffic)
+	var socket string
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Linha da conexão: "ESTAB 0 0 local remoto users:((...))". Os
+		// detalhes (bytes_sent, bytes_received) vêm na linha indentada seguinte.
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			socket = ""
+			fields := strings.Fields(line)
+			match := ssPIDPattern.FindStringSubmatch(line)
+			if len(fields) >= 5 && match != nil {
+				socket = match[1] + ":" + fields[3] + ">" + fields[4]
+			}
+			continue
+		}
+		if socket == "" {
+			continue
+		}
+
+		var sent, acked, received uint64
+		var hasSent bool
+		for _, field := range strings.Fields(line) {
+			key, value, ok := strings.Cut(field, ":")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "bytes_sent":
+				sent, _ = strconv.ParseUint(value, 10, 64)
+				hasSent = true
+			case "bytes_acked":
+				acked, _ = strconv.ParseUint(value, 10, 64)
+			case "bytes_received":
+				received, _ = strconv.ParseUint(value, 10, 64)
+			}
+		}
+		// Kernels antigos só têm bytes_acked.
+		if !hasSent {
+			sent = acked
+		}
+
+		traffic[socket+":sent"] = sent
+		traffic[socket+":recv"] = received
+		socket = ""
+	}
+
+	return traffic
+}
+
+// socketPID extrai o PID da chave "pid:local>remoto:sent".
+func socketPID(key string) int {
+	pid, _, _ := strings.Cut(key, ":")
+	n, _ := strconv.Atoi(pid)
+	return n
+}
+
+// perPID soma, por processo, quanto cada socket trafegou no intervalo. As
+// chaves do intervalo são as de t com prefix na frente. Conexões abertas
+// depois da coleta anterior só têm a leitura de agora, que serve de base
+// para o próximo ciclo; o contador delas cobre a vida toda da conexão e não
+// entra no intervalo.
+func (t socketTraffic) perPID(interval sampler.Interval, prefix string) (sent, recv map[int]uint64) {
+	sent = make(map[int]uint64)
+	recv = make(map[int]uint64)
+	for key := range t {
+		d, ok := interval.Delta(prefix + key)
+		if !ok {
+			continue
+		}
+		if strings.HasSuffix(key, ":sent") {
+			sent[socketPID(key)] += d
+		} else {
+			recv[socketPID(key)] += d
+		}
+	}
+	return sent, recv
+}
+
+// countSockets conta os descritores do processo que apontam para sockets.
+func countSockets(pid int) int {
+	dir := filepath.Join("/proc", strconv.Itoa(pid), "fd")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	var sockets int
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join(dir, entry.Name()))
+		if err == nil && strings.HasPrefix(target, "socket:[") {
+			sockets++
+		}
+	}
+	return sockets
+}
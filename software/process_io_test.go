@@ -0,0 +1,64 @@
+package software
+
+import (
+	"testing"
+
+	"monitoramento/sampler"
+)
+
+func TestSocketTrafficPerPID(t *testing.T) {
+	cycles := []socketTraffic{
+		{
+			"100:10.0.0.2:22>10.0.0.9:51000:sent": 5000,
+			"100:10.0.0.2:22>10.0.0.9:51000:recv": 800,
+		},
+		{
+			"100:10.0.0.2:22>10.0.0.9:51000:sent": 7000,
+			"100:10.0.0.2:22>10.0.0.9:51000:recv": 900,
+			// Conexão nova com um contador grande desde a abertura.
+			"200:10.0.0.2:443>10.0.0.7:40000:sent": 9000000,
+			"200:10.0.0.2:443>10.0.0.7:40000:recv": 4000000,
+		},
+		{
+			"100:10.0.0.2:22>10.0.0.9:51000:sent":  7500,
+			"100:10.0.0.2:22>10.0.0.9:51000:recv":  900,
+			"200:10.0.0.2:443>10.0.0.7:40000:sent": 9000300,
+			"200:10.0.0.2:443>10.0.0.7:40000:recv": 4000100,
+		},
+	}
+
+	var s sampler.Sampler
+	var traffic socketTraffic
+	sample := func() sampler.Interval {
+		interval, err := s.Sample(func() (sampler.Snapshot, error) {
+			traffic, cycles = cycles[0], cycles[1:]
+			snapshot := make(sampler.Snapshot)
+			for key, value := range traffic {
+				snapshot["socket:"+key] = value
+			}
+			return snapshot, nil
+		})
+		if err != nil && err != sampler.ErrPriming {
+			t.Fatal(err)
+		}
+		return interval
+	}
+
+	sampler.Warm(func() { sample() })
+
+	sent, recv := traffic.perPID(sample(), "socket:")
+	if sent[100] != 2000 || recv[100] != 100 {
+		t.Errorf("esperado 2000/100 bytes para o PID 100, obtido %d/%d", sent[100], recv[100])
+	}
+	if sent[200] != 0 || recv[200] != 0 {
+		t.Errorf("conexão nova não deveria entrar no intervalo, obtido %d/%d", sent[200], recv[200])
+	}
+
+	sent, recv = traffic.perPID(sample(), "socket:")
+	if sent[100] != 500 || recv[100] != 0 {
+		t.Errorf("esperado 500/0 bytes para o PID 100, obtido %d/%d", sent[100], recv[100])
+	}
+	if sent[200] != 300 || recv[200] != 100 {
+		t.Errorf("esperado 300/100 bytes para o PID 200 a partir da base, obtido %d/%d", sent[200], recv[200])
+	}
+}
@@ -0,0 +1,127 @@
+package software
+
+import (
+	"log"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Service descreve um serviço do sistema. Status segue o vocabulário do
+// systemd (active, inactive, failed, activating, deactivating, unknown) em
+// todos os sistemas de init do Linux, e SubState guarda o estado como o
+// próprio init informa; no Windows Status é o STATE do sc. Os demais
+// campos só são preenchidos quando o gerenciador de serviços informa.
+type Service struct {
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	Description string    `json:"description,omitempty"`
+	LoadState   string    `json:"load_state,omitempty"`
+	SubState    string    `json:"sub_state,omitempty"`
+	Enabled     string    `json:"enabled,omitempty"`
+	MainPID     int32     `json:"main_pid,omitempty"`
+	MemoryBytes uint64    `json:"memory_bytes,omitempty"`
+	CPUUsage    float64   `json:"cpu_usage,omitempty"`
+	Restarts    uint32    `json:"restarts,omitempty"`
+	StateChange time.Time `json:"state_change"`
+	Failed      bool      `json:"failed"`
+	Manager     string    `json:"manager,omitempty"`
+
+	cpuNSec uint64
+}
+
+// serviceBackend é um sistema de init. Ao contrário dos gerenciadores de
+// pacotes, só um é usado: o primeiro disponível em serviceBackends.
+type serviceBackend interface {
+	Name() string
+	Available() bool
+	List() ([]Service, error)
+}
+
+// A ordem importa: máquinas com systemd costumam manter /etc/init.d, e
+// containers com s6 ou runit podem ter o OpenRC instalado sem ter subido
+// por ele, por isso o SysV fica por último e cada backend confere se o
+// init está de fato rodando.
+var serviceBackends = newServiceBackends(defaultRoot)
+
+func newServiceBackends(root string) []serviceBackend {
+	return []serviceBackend{
+		systemdBackend{root: root},
+		s6Backend{root: root},
+		runitBackend{root: root},
+		openrcBackend{root: root},
+		sysvBackend{root: root},
+	}
+}
+
+// detectServiceBackend devolve o primeiro backend disponível, ou nil.
+func detectServiceBackend(backends []serviceBackend) serviceBackend {
+	for _, backend := range backends {
+		if backend.Available() {
+			return backend
+		}
+	}
+	return nil
+}
+
+func getSystemServices() ([]Service, error) {
+	if runtime.GOOS == "windows" {
+		return getWindowsServices()
+	}
+
+	backend := detectServiceBackend(serviceBackends)
+	if backend == nil {
+		log.Printf("Nenhum sistema de init reconhecido; serviços não serão coletados")
+		return nil, nil
+	}
+
+	services, err := backend.List()
+	if err != nil {
+		return nil, err
+	}
+	for i := range services {
+		services[i].Manager = backend.Name()
+	}
+	sortServices(services)
+	return services, nil
+}
+
+func getWindowsServices() ([]Service, error) {
+	var services []Service
+
+	cmd := exec.Command("sc", "query", "type=", "service", "state=", "all")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(output), "\n")
+	for i := 0; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "SERVICE_NAME:") {
+			name := strings.TrimSpace(strings.TrimPrefix(lines[i], "SERVICE_NAME:"))
+			status := "Unknown"
+			for j := i + 1; j < len(lines) && j < i+5; j++ {
+				if strings.HasPrefix(lines[j], "        STATE") {
+					status = strings.TrimSpace(strings.TrimPrefix(lines[j], "        STATE              :"))
+					break
+				}
+			}
+			services = append(services, Service{Name: name, Status: status, Manager: "windows"})
+		}
+	}
+
+	return services, nil
+}
+
+// sortServices põe os serviços com falha na frente, para chamar atenção no
+// relatório, e o resto em ordem de nome.
+func sortServices(services []Service) {
+	sort.SliceStable(services, func(i, j int) bool {
+		if services[i].Failed != services[j].Failed {
+			return services[i].Failed
+		}
+		return services[i].Name < services[j].Name
+	})
+}
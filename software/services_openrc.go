@@ -0,0 +1,127 @@
+package software
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// openrcBackend lê o rc-status (Alpine, Gentoo). Habilitado quer dizer
+// estar em algum runlevel de /etc/runlevels.
+type openrcBackend struct {
+	root string
+}
+
+func (b openrcBackend) Name() string { return "openrc" }
+
+// Available exige o /run/openrc, criado no boot: em containers o OpenRC
+// costuma estar instalado sem ter iniciado nada.
+func (b openrcBackend) Available() bool {
+	return commandExists("rc-status") && fileExists(rootPath(b.root, "run/openrc"))
+}
+
+func (b openrcBackend) List() ([]Service, error) {
+	output, err := exec.Command("rc-status", "--servicelist", "--nocolor").Output()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao executar rc-status: %v", err)
+	}
+
+	services := parseRCStatus(string(output), time.Now())
+	for i := range services {
+		matches, _ := filepath.Glob(rootPath(b.root, "etc/runlevels/*", services[i].Name))
+		services[i].Enabled = "disabled"
+		if len(matches) > 0 {
+			services[i].Enabled = "enabled"
+		}
+	}
+	return services, nil
+}
+
+var openrcStates = map[string]string{
+	"started":  "active",
+	"stopped":  "inactive",
+	"inactive": "inactive",
+	"crashed":  "failed",
+	"failed":   "failed",
+	"starting": "activating",
+	"stopping": "deactivating",
+}
+
+// parseRCStatus lê linhas como " sshd   [  started  ]". Serviços sob o
+// supervise-daemon trazem também o uptime e o número de reinícios:
+// "[  started 2 day(s) 01:02:03 (1)  ]".
+func parseRCStatus(output string, now time.Time) []Service {
+	var services []Service
+
+	for _, line := range strings.Split(output, "\n") {
+		open, close := strings.Index(line, "["), strings.LastIndex(line, "]")
+		if open < 0 || close < open {
+			continue
+		}
+		name := strings.TrimSpace(line[:open])
+		fields := strings.Fields(line[open+1 : close])
+		if name == "" || len(fields) == 0 {
+			continue
+		}
+
+		state := fields[0]
+		s := Service{
+			Name:     name,
+			Status:   "unknown",
+			SubState: state,
+			Failed:   openrcStates[state] == "failed",
+		}
+		if status, ok := openrcStates[state]; ok {
+			s.Status = status
+		}
+
+		extra := fields[1:]
+		if n := len(extra); n > 0 && strings.HasPrefix(extra[n-1], "(") {
+			restarts, err := strconv.ParseUint(strings.Trim(extra[n-1], "()"), 10, 32)
+			if err == nil {
+				s.Restarts = uint32(restarts)
+			}
+			extra = extra[:n-1]
+		}
+		if uptime, ok := parseOpenRCUptime(extra); ok {
+			s.StateChange = now.Add(-uptime)
+		}
+
+		services = append(services, s)
+	}
+
+	return services
+}
+
+// parseOpenRCUptime entende "HH:MM:SS", "MM:SS" e o prefixo "N day(s)".
+func parseOpenRCUptime(fields []string) (time.Duration, bool) {
+	if len(fields) == 0 {
+		return 0, false
+	}
+
+	var days time.Duration
+	if len(fields) == 3 && strings.HasPrefix(fields[1], "day") {
+		n, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return 0, false
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		fields = fields[2:]
+	}
+	if len(fields) != 1 {
+		return 0, false
+	}
+
+	var clock time.Duration
+	for _, part := range strings.Split(fields[0], ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, false
+		}
+		clock = clock*60 + time.Duration(n)*time.Second
+	}
+	return days + clock, true
+}
@@ -0,0 +1,58 @@
+package software
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRCStatus(t *testing.T) {
+	output := `Runlevel: default
+ sshd                                  [  started  ]
+ crond                                 [  started 2 day(s) 01:02:03 (1)  ]
+ nginx                                 [  started 05:10 (0)  ]
+ chronyd                               [  crashed  ]
+ local                                 [  stopped  ]
+ networkmanager                        [  starting  ]
+ estranho                              [  hibernando  ]
+Dynamic Runlevel: manual
+`
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	want := []Service{
+		{Name: "sshd", Status: "active", SubState: "started"},
+		{Name: "crond", Status: "active", SubState: "started", Restarts: 1, StateChange: now.Add(-(2*24*time.Hour + time.Hour + 2*time.Minute + 3*time.Second))},
+		{Name: "nginx", Status: "active", SubState: "started", StateChange: now.Add(-(5*time.Minute + 10*time.Second))},
+		{Name: "chronyd", Status: "failed", SubState: "crashed", Failed: true},
+		{Name: "local", Status: "inactive", SubState: "stopped"},
+		{Name: "networkmanager", Status: "activating", SubState: "starting"},
+		{Name: "estranho", Status: "unknown", SubState: "hibernando"},
+	}
+	if got := parseRCStatus(output, now); !reflect.DeepEqual(got, want) {
+		t.Errorf("serviços inesperados:\n obtido  %+v\n esperado %+v", got, want)
+	}
+}
+
+func TestParseOpenRCUptime(t *testing.T) {
+	tests := []struct {
+		fields []string
+		want   time.Duration
+		ok     bool
+	}{
+		{[]string{"01:02:03"}, time.Hour + 2*time.Minute + 3*time.Second, true},
+		{[]string{"05:10"}, 5*time.Minute + 10*time.Second, true},
+		{[]string{"1", "day(s)", "00:00:30"}, 24*time.Hour + 30*time.Second, true},
+		{[]string{"12", "day(s)", "23:59:59"}, 12*24*time.Hour + 23*time.Hour + 59*time.Minute + 59*time.Second, true},
+		{nil, 0, false},
+		{[]string{"x", "day(s)", "00:00:30"}, 0, false},
+		{[]string{"01:xx:03"}, 0, false},
+		{[]string{"01:02:03", "extra"}, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseOpenRCUptime(tt.fields)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseOpenRCUptime(%q) = %v, %v; esperado %v, %v", tt.fields, got, ok, tt.want, tt.ok)
+		}
+	}
+}
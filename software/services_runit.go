@@ -0,0 +1,216 @@
+package software
+
+import (
+	"encoding/binary"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Diretórios de serviços supervisionados, na ordem em que são procurados.
+var (
+	runitServiceDirs = []string{"run/runit/service", "var/service", "etc/service"}
+	s6ServiceDirs    = []string{"run/service", "var/run/s6/services", "run/s6/services", "service"}
+)
+
+func firstServiceDir(root string, dirs []string) string {
+	for _, dir := range dirs {
+		if info, err := os.Stat(rootPath(root, dir)); err == nil && info.IsDir() {
+			return rootPath(root, dir)
+		}
+	}
+	return ""
+}
+
+// serviceEntries lista os serviços de um diretório de supervisão; as
+// entradas costumam ser links para a definição real do serviço.
+func serviceEntries(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(dir, entry.Name())); err == nil && info.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// runitBackend lê direto o supervise/ de cada serviço, que o runsv mantém
+// atualizado; ler esses arquivos exige as mesmas permissões do sv status.
+type runitBackend struct {
+	root string
+}
+
+func (b runitBackend) Name() string { return "runit" }
+
+func (b runitBackend) Available() bool {
+	return commandExists("runsvdir") && firstServiceDir(b.root, runitServiceDirs) != ""
+}
+
+func (b runitBackend) List() ([]Service, error) {
+	dir := firstServiceDir(b.root, runitServiceDirs)
+	names, err := serviceEntries(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var services []Service
+	for _, name := range names {
+		services = append(services, readRunitService(filepath.Join(dir, name), name))
+	}
+	return services, nil
+}
+
+// readRunitService monta o serviço a partir de supervise/stat ("run",
+// "down" ou "finish", às vezes com ", paused" e afins), supervise/pid e
+// supervise/status, de onde sai o horário da última mudança (TAI64N nos
+// primeiros 12 bytes). Sem o arquivo "down" o serviço sobe com o sistema,
+// então estar parado nesse caso conta como falha.
+func readRunitService(dir, name string) Service {
+	s := Service{Name: name, Status: "unknown", Enabled: "enabled"}
+	normallyUp := !fileExists(filepath.Join(dir, "down"))
+	if !normallyUp {
+		s.Enabled = "disabled"
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "supervise/stat"))
+	if err != nil {
+		return s
+	}
+	s.SubState = strings.TrimSpace(string(data))
+
+	state, _, _ := strings.Cut(s.SubState, ",")
+	switch state {
+	case "run":
+		s.Status = "active"
+	case "finish":
+		s.Status = "deactivating"
+	case "down":
+		s.Status = "inactive"
+		if normallyUp {
+			s.Status, s.Failed = "failed", true
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "supervise/pid")); err == nil {
+		if pid, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 32); err == nil {
+			s.MainPID = int32(pid)
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "supervise/status")); err == nil && len(data) >= 12 {
+		s.StateChange = tai64nTime(data[:12])
+	}
+
+	return s
+}
+
+// tai64nTime converte um rótulo TAI64N. O deslocamento de 2^62 + 10 é o
+// que o daemontools e o runit usam, ignorando os segundos bissextos.
+func tai64nTime(label []byte) time.Time {
+	secs := binary.BigEndian.Uint64(label[:8])
+	nanos := binary.BigEndian.Uint32(label[8:12])
+	const offset = 1<<62 + 10
+	if secs < offset {
+		return time.Time{}
+	}
+	return time.Unix(int64(secs-offset), int64(nanos))
+}
+
+// s6Backend usa o s6-svstat, já que o formato do supervise/status mudou
+// entre versões do s6.
+type s6Backend struct {
+	root string
+}
+
+func (b s6Backend) Name() string { return "s6" }
+
+func (b s6Backend) Available() bool {
+	return commandExists("s6-svstat") && firstServiceDir(b.root, s6ServiceDirs) != ""
+}
+
+func (b s6Backend) List() ([]Service, error) {
+	dir := firstServiceDir(b.root, s6ServiceDirs)
+	names, err := serviceEntries(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var services []Service
+	for _, name := range names {
+		s := Service{Name: name, Status: "unknown"}
+		output, err := exec.Command("s6-svstat", filepath.Join(dir, name)).Output()
+		if err == nil {
+			s = parseS6Status(name, string(output), now)
+		}
+		services = append(services, s)
+	}
+	return services, nil
+}
+
+var (
+	s6PIDPattern     = regexp.MustCompile(`\(pid (\d+)`)
+	s6SecondsPattern = regexp.MustCompile(`\) (\d+) seconds|^\w+ (\d+) seconds`)
+)
+
+// parseS6Status lê a saída do s6-svstat:
+//
+//	up (pid 1234 pgid 1234) 56 seconds, normally down, ready 56 seconds
+//	down (exitcode 1) 3 seconds, normally up, want up
+//
+// "normally up/down" só aparece quando o estado atual difere do padrão.
+func parseS6Status(name, output string, now time.Time) Service {
+	output = strings.TrimSpace(output)
+	state, _, _ := strings.Cut(output, " ")
+	s := Service{Name: name, Status: "unknown", SubState: state}
+
+	if open, close := strings.Index(output, "("), strings.Index(output, ")"); open >= 0 && close > open {
+		s.SubState = output[:close+1]
+	}
+
+	up := state == "up"
+	normallyUp := up
+	if strings.Contains(output, "normally up") {
+		normallyUp = true
+	} else if strings.Contains(output, "normally down") {
+		normallyUp = false
+	}
+	s.Enabled = "disabled"
+	if normallyUp {
+		s.Enabled = "enabled"
+	}
+
+	switch {
+	case up:
+		s.Status = "active"
+	case state == "down" && (normallyUp || strings.Contains(output, "want up")):
+		s.Status, s.Failed = "failed", true
+	case state == "down":
+		s.Status = "inactive"
+	}
+
+	if m := s6PIDPattern.FindStringSubmatch(output); m != nil {
+		if pid, err := strconv.ParseInt(m[1], 10, 32); err == nil {
+			s.MainPID = int32(pid)
+		}
+	}
+	if m := s6SecondsPattern.FindStringSubmatch(output); m != nil {
+		if secs, err := strconv.Atoi(m[1] + m[2]); err == nil {
+			s.StateChange = now.Add(-time.Duration(secs) * time.Second)
+		}
+	}
+
+	return s
+}
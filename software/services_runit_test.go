@@ -0,0 +1,105 @@
+package software
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func tai64nLabel(t time.Time) []byte {
+	label := make([]byte, 12)
+	binary.BigEndian.PutUint64(label, uint64(t.Unix())+1<<62+10)
+	binary.BigEndian.PutUint32(label[8:], uint32(t.Nanosecond()))
+	return label
+}
+
+func TestTAI64NTime(t *testing.T) {
+	want := time.Unix(1700000000, 500)
+	if got := tai64nTime(tai64nLabel(want)); !got.Equal(want) {
+		t.Errorf("esperado %v, obtido %v", want, got)
+	}
+	if got := tai64nTime(make([]byte, 12)); !got.IsZero() {
+		t.Errorf("rótulo antes da época deveria dar zero, obtido %v", got)
+	}
+}
+
+func TestRunitList(t *testing.T) {
+	root := t.TempDir()
+	changed := time.Unix(1700000000, 0)
+	// status do runsv: TAI64N nos 12 primeiros bytes, o resto não é lido.
+	status := string(append(tai64nLabel(changed), make([]byte, 8)...))
+
+	dir := "etc/service"
+	writeRootFile(t, root, dir+"/sshd/supervise/stat", "run\n", 0644)
+	writeRootFile(t, root, dir+"/sshd/supervise/pid", "812\n", 0644)
+	writeRootFile(t, root, dir+"/sshd/supervise/status", status, 0644)
+	// Sem o arquivo down o cron deveria estar rodando.
+	writeRootFile(t, root, dir+"/cron/supervise/stat", "down\n", 0644)
+	writeRootFile(t, root, dir+"/getty/down", "", 0644)
+	writeRootFile(t, root, dir+"/getty/supervise/stat", "down\n", 0644)
+	writeRootFile(t, root, dir+"/ntpd/supervise/stat", "run, paused\n", 0644)
+	writeRootFile(t, root, dir+"/semsupervise/run", "#!/bin/sh\n", 0755)
+	writeRootFile(t, root, dir+"/.oculto/supervise/stat", "run\n", 0644)
+
+	services, err := runitBackend{root: root}.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Service{
+		{Name: "cron", Status: "failed", SubState: "down", Enabled: "enabled", Failed: true},
+		{Name: "getty", Status: "inactive", SubState: "down", Enabled: "disabled"},
+		{Name: "ntpd", Status: "active", SubState: "run, paused", Enabled: "enabled"},
+		{Name: "semsupervise", Status: "unknown", Enabled: "enabled"},
+		{Name: "sshd", Status: "active", SubState: "run", Enabled: "enabled", MainPID: 812, StateChange: changed},
+	}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("serviços inesperados:\n obtido  %+v\n esperado %+v", services, want)
+	}
+
+	if got := firstServiceDir(root, runitServiceDirs); got != filepath.Join(root, dir) {
+		t.Errorf("diretório de serviços inesperado: %s", got)
+	}
+}
+
+func TestParseS6Status(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		output string
+		want   Service
+	}{
+		{
+			"up (pid 1234 pgid 1234) 56 seconds, normally down, ready 56 seconds\n",
+			Service{Name: "svc", Status: "active", SubState: "up (pid 1234 pgid 1234)", Enabled: "disabled", MainPID: 1234, StateChange: now.Add(-56 * time.Second)},
+		},
+		{
+			"up (pid 77) 120 seconds\n",
+			Service{Name: "svc", Status: "active", SubState: "up (pid 77)", Enabled: "enabled", MainPID: 77, StateChange: now.Add(-120 * time.Second)},
+		},
+		{
+			"down (exitcode 1) 3 seconds, normally up, want up\n",
+			Service{Name: "svc", Status: "failed", SubState: "down (exitcode 1)", Enabled: "enabled", Failed: true, StateChange: now.Add(-3 * time.Second)},
+		},
+		{
+			"down (signal SIGTERM) 5 seconds, want up\n",
+			Service{Name: "svc", Status: "failed", SubState: "down (signal SIGTERM)", Enabled: "disabled", Failed: true, StateChange: now.Add(-5 * time.Second)},
+		},
+		{
+			"down (exitcode 0) 10 seconds, ready 10 seconds\n",
+			Service{Name: "svc", Status: "inactive", SubState: "down (exitcode 0)", Enabled: "disabled", StateChange: now.Add(-10 * time.Second)},
+		},
+		{
+			"down 42 seconds, normally up\n",
+			Service{Name: "svc", Status: "failed", SubState: "down", Enabled: "enabled", Failed: true, StateChange: now.Add(-42 * time.Second)},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := parseS6Status("svc", tt.output, now); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseS6Status(%q):\n obtido  %+v\n esperado %+v", tt.output, got, tt.want)
+		}
+	}
+}
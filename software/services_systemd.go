@@ -0,0 +1,275 @@
+package software
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+
+	"monitoramento/sampler"
+	"monitoramento/utils"
+)
+
+const (
+	systemdDest      = "org.freedesktop.systemd1"
+	systemdPath      = "/org/freedesktop/systemd1"
+	systemdUnit      = "org.freedesktop.systemd1.Unit"
+	systemdService   = "org.freedesktop.systemd1.Service"
+	dbusCallTimeout  = 5 * time.Second
+	systemdUnsetUint = math.MaxUint64
+)
+
+// systemdUnitStatus é uma linha do Manager.ListUnits, assinatura (ssssssouso).
+type systemdUnitStatus struct {
+	Name        string
+	Description string
+	LoadState   string
+	ActiveState string
+	SubState    string
+	Followed    string
+	Path        dbus.ObjectPath
+	JobID       uint32
+	JobType     string
+	JobPath     dbus.ObjectPath
+}
+
+// systemdBus é o que o coletor usa da API D-Bus do systemd. A implementação
+// real conversa com o barramento do sistema; um responder falso pode
+// devolver unidades e propriedades prontas.
+type systemdBus interface {
+	ListUnits() ([]systemdUnitStatus, error)
+	GetAll(path dbus.ObjectPath, iface string) (map[string]dbus.Variant, error)
+	Close() error
+}
+
+type dbusSystemd struct {
+	conn *dbus.Conn
+}
+
+func connectSystemd() (systemdBus, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar no D-Bus do sistema: %v", err)
+	}
+	return &dbusSystemd{conn: conn}, nil
+}
+
+func (d *dbusSystemd) ListUnits() ([]systemdUnitStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbusCallTimeout)
+	defer cancel()
+
+	var units []systemdUnitStatus
+	err := d.conn.Object(systemdDest, systemdPath).
+		CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ListUnits", 0).
+		Store(&units)
+	return units, err
+}
+
+func (d *dbusSystemd) GetAll(path dbus.ObjectPath, iface string) (map[string]dbus.Variant, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbusCallTimeout)
+	defer cancel()
+
+	var props map[string]dbus.Variant
+	err := d.conn.Object(systemdDest, path).
+		CallWithContext(ctx, "org.freedesktop.DBus.Properties.GetAll", 0, iface).
+		Store(&props)
+	return props, err
+}
+
+func (d *dbusSystemd) Close() error {
+	return d.conn.Close()
+}
+
+// systemdBackend usa a API D-Bus do systemd e, se o barramento não
+// responder, a saída do systemctl.
+type systemdBackend struct {
+	root string
+}
+
+func (b systemdBackend) Name() string { return "systemd" }
+
+// Available faz o mesmo teste do sd_booted(): o diretório só existe quando
+// o systemd é o init.
+func (b systemdBackend) Available() bool {
+	return fileExists(rootPath(b.root, "run/systemd/system"))
+}
+
+func (b systemdBackend) List() ([]Service, error) {
+	bus, err := connectSystemd()
+	if err == nil {
+		defer bus.Close()
+		return getSystemdServices(bus, utils.CgroupRoot(b.root))
+	}
+	log.Printf("Erro ao consultar o systemd via D-Bus, usando systemctl: %v", err)
+
+	cmd := exec.Command("systemctl", "list-units", "--type=service", "--all", "--no-pager", "--no-legend")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao executar systemctl: %v", err)
+	}
+	return parseSystemctlUnits(string(output)), nil
+}
+
+// parseSystemctlUnits lê as colunas UNIT LOAD ACTIVE SUB DESCRIPTION; as
+// unidades com falha vêm marcadas com "●" antes do nome.
+func parseSystemctlUnits(output string) []Service {
+	var services []Service
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(strings.TrimLeft(strings.TrimSpace(line), "●* "))
+		if len(fields) < 4 {
+			continue
+		}
+		services = append(services, Service{
+			Name:        fields[0],
+			Status:      fields[2],
+			Description: strings.Join(fields[4:], " "),
+			LoadState:   fields[1],
+			SubState:    fields[3],
+			Failed:      fields[2] == "failed",
+		})
+	}
+	return services
+}
+
+var serviceSampler sampler.Sampler
+
+// getSystemdServices lista os serviços carregados com os detalhes de cada
+// unidade. CPU vem do CPUUsageNSec comparado com o ciclo anterior; quando
+// a contabilidade do systemd está desligada, memória e CPU são lidas
+// direto do cgroup da unidade.
+func getSystemdServices(bus systemdBus, cgroupRoot string) ([]Service, error) {
+	var services []Service
+
+	interval, err := serviceSampler.Sample(func() (sampler.Snapshot, error) {
+		var err error
+		services, err = readSystemdServices(bus, cgroupRoot)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot := make(sampler.Snapshot)
+		for _, s := range services {
+			if s.cpuNSec != systemdUnsetUint {
+				snapshot[s.Name+":cpu"] = s.cpuNSec
+			}
+		}
+		return snapshot, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range services {
+		services[i].CPUUsage = interval.Rate(services[i].Name+":cpu") / 1e9 * 100
+	}
+
+	return services, nil
+}
+
+func readSystemdServices(bus systemdBus, cgroupRoot string) ([]Service, error) {
+	units, err := bus.ListUnits()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar unidades do systemd: %v", err)
+	}
+
+	var services []Service
+	for _, u := range units {
+		if !strings.HasSuffix(u.Name, ".service") {
+			continue
+		}
+
+		s := Service{
+			Name:        u.Name,
+			Status:      u.ActiveState,
+			Description: u.Description,
+			LoadState:   u.LoadState,
+			SubState:    u.SubState,
+			Failed:      u.ActiveState == "failed",
+			cpuNSec:     systemdUnsetUint,
+		}
+
+		// Unidades não encontradas ou mascaradas não têm propriedades úteis.
+		// Se o GetAll falhar (a unidade pode sumir entre o ListUnits e o
+		// GetAll), ela continua na lista só com o que veio do ListUnits.
+		if u.LoadState == "loaded" {
+			if err := fillSystemdDetails(bus, u.Path, cgroupRoot, &s); err != nil {
+				log.Printf("Erro ao ler propriedades de %s: %v", u.Name, err)
+			}
+		}
+
+		services = append(services, s)
+	}
+
+	return services, nil
+}
+
+func fillSystemdDetails(bus systemdBus, path dbus.ObjectPath, cgroupRoot string, s *Service) error {
+	unit, err := bus.GetAll(path, systemdUnit)
+	if err != nil {
+		return err
+	}
+	service, err := bus.GetAll(path, systemdService)
+	if err != nil {
+		return err
+	}
+
+	s.Enabled = variantString(unit, "UnitFileState")
+	if usec := variantUint64(unit, "StateChangeTimestamp"); usec > 0 {
+		s.StateChange = time.UnixMicro(int64(usec))
+	}
+
+	s.MainPID = int32(variantUint64(service, "MainPID"))
+	s.Restarts = uint32(variantUint64(service, "NRestarts"))
+
+	s.MemoryBytes = variantUint64(service, "MemoryCurrent")
+	s.cpuNSec = variantUint64(service, "CPUUsageNSec")
+
+	if cgroup := variantString(service, "ControlGroup"); cgroup != "" && cgroupRoot != "" {
+		dir := filepath.Join(cgroupRoot, cgroup)
+		if s.MemoryBytes == systemdUnsetUint {
+			s.MemoryBytes, _ = utils.ReadCgroupUint(filepath.Join(dir, "memory.current"))
+		}
+		if s.cpuNSec == systemdUnsetUint {
+			if usec, ok := utils.ReadKeyValues(filepath.Join(dir, "cpu.stat"))["usage_usec"]; ok {
+				s.cpuNSec = usec * 1000
+			}
+		}
+	}
+	if s.MemoryBytes == systemdUnsetUint {
+		s.MemoryBytes = 0
+	}
+
+	return nil
+}
+
+func variantString(props map[string]dbus.Variant, key string) string {
+	v, ok := props[key]
+	if !ok {
+		return ""
+	}
+	s, _ := v.Value().(string)
+	return s
+}
+
+// variantUint64 aceita os tipos inteiros que o systemd usa (u e t).
+func variantUint64(props map[string]dbus.Variant, key string) uint64 {
+	v, ok := props[key]
+	if !ok {
+		return 0
+	}
+	switch n := v.Value().(type) {
+	case uint64:
+		return n
+	case uint32:
+		return uint64(n)
+	case int32:
+		return uint64(n)
+	}
+	return 0
+}
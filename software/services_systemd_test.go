@@ -0,0 +1,170 @@
+package software
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// fakeSystemd responde como o systemd no D-Bus, com unidades e
+// propriedades prontas.
+type fakeSystemd struct {
+	units  []systemdUnitStatus
+	props  map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	cpu    map[dbus.ObjectPath]uint64
+	cpuInc uint64
+}
+
+func (f *fakeSystemd) ListUnits() ([]systemdUnitStatus, error) { return f.units, nil }
+
+func (f *fakeSystemd) GetAll(path dbus.ObjectPath, iface string) (map[string]dbus.Variant, error) {
+	props, ok := f.props[path][iface]
+	if !ok {
+		return nil, errors.New("org.freedesktop.DBus.Error.UnknownObject")
+	}
+	if iface == systemdService {
+		if _, ok := f.cpu[path]; ok {
+			f.cpu[path] += f.cpuInc
+			props["CPUUsageNSec"] = dbus.MakeVariant(f.cpu[path])
+		}
+	}
+	return props, nil
+}
+
+func (f *fakeSystemd) Close() error { return nil }
+
+func newFakeSystemd() *fakeSystemd {
+	return &fakeSystemd{
+		units: []systemdUnitStatus{
+			{Name: "nginx.service", Description: "Servidor web", LoadState: "loaded", ActiveState: "active", SubState: "running", Path: "/unit/nginx"},
+			{Name: "backup.service", Description: "Backup", LoadState: "loaded", ActiveState: "failed", SubState: "failed", Path: "/unit/backup"},
+			{Name: "sumiu.service", LoadState: "loaded", ActiveState: "inactive", SubState: "dead", Path: "/unit/sumiu"},
+			{Name: "antigo.service", LoadState: "not-found", ActiveState: "inactive", SubState: "dead", Path: "/unit/antigo"},
+			{Name: "sshd.socket", LoadState: "loaded", ActiveState: "active", SubState: "listening", Path: "/unit/sshd_socket"},
+		},
+		props: map[dbus.ObjectPath]map[string]map[string]dbus.Variant{
+			"/unit/nginx": {
+				systemdUnit: {
+					"UnitFileState":        dbus.MakeVariant("enabled"),
+					"StateChangeTimestamp": dbus.MakeVariant(uint64(1700000000000000)),
+				},
+				systemdService: {
+					"MainPID":       dbus.MakeVariant(uint32(4242)),
+					"NRestarts":     dbus.MakeVariant(uint32(0)),
+					"MemoryCurrent": dbus.MakeVariant(uint64(64 << 20)),
+					"ControlGroup":  dbus.MakeVariant("/system.slice/nginx.service"),
+				},
+			},
+			"/unit/backup": {
+				systemdUnit: {"UnitFileState": dbus.MakeVariant("disabled")},
+				systemdService: {
+					"MainPID":       dbus.MakeVariant(uint32(0)),
+					"NRestarts":     dbus.MakeVariant(uint32(3)),
+					"MemoryCurrent": dbus.MakeVariant(uint64(systemdUnsetUint)),
+					"CPUUsageNSec":  dbus.MakeVariant(uint64(systemdUnsetUint)),
+					"ControlGroup":  dbus.MakeVariant("/system.slice/backup.service"),
+				},
+			},
+		},
+		cpu: map[dbus.ObjectPath]uint64{"/unit/nginx": 0},
+	}
+}
+
+func TestReadSystemdServices(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "system.slice/backup.service")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "memory.current"), []byte("4096\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "cpu.stat"), []byte("usage_usec 1500\nuser_usec 1000\n"), 0644)
+
+	services, err := readSystemdServices(newFakeSystemd(), root)
+	if err != nil {
+		t.Fatalf("readSystemdServices: %v", err)
+	}
+
+	byName := make(map[string]Service)
+	for _, s := range services {
+		byName[s.Name] = s
+	}
+	if len(services) != 4 {
+		t.Fatalf("esperados nginx, backup, sumiu e antigo (socket filtrado), veio %+v", services)
+	}
+
+	nginx := byName["nginx.service"]
+	if nginx.Status != "active" || nginx.SubState != "running" || nginx.LoadState != "loaded" ||
+		nginx.Enabled != "enabled" || nginx.MainPID != 4242 || nginx.MemoryBytes != 64<<20 ||
+		nginx.Description != "Servidor web" || !nginx.StateChange.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("nginx incompleto: %+v", nginx)
+	}
+
+	// Sem contabilidade no systemd, memória e CPU vêm do cgroup.
+	backup := byName["backup.service"]
+	if !backup.Failed || backup.Restarts != 3 || backup.MemoryBytes != 4096 || backup.cpuNSec != 1500*1000 {
+		t.Errorf("backup incompleto: %+v", backup)
+	}
+
+	// Sem propriedades no D-Bus, fica o que veio do ListUnits.
+	if sumiu := byName["sumiu.service"]; sumiu.Status != "inactive" || sumiu.SubState != "dead" ||
+		sumiu.LoadState != "loaded" || sumiu.Enabled != "" || sumiu.cpuNSec != systemdUnsetUint {
+		t.Errorf("unidade sem propriedades deveria manter os campos do ListUnits: %+v", sumiu)
+	}
+
+	if antigo := byName["antigo.service"]; antigo.LoadState != "not-found" || antigo.Enabled != "" {
+		t.Errorf("unidade não encontrada não deveria ter detalhes: %+v", antigo)
+	}
+}
+
+func TestGetSystemdServicesCPU(t *testing.T) {
+	bus := newFakeSystemd()
+	bus.cpuInc = 250_000_000 // 0,25 s de CPU por leitura
+
+	services, err := getSystemdServices(bus, "")
+	if err != nil {
+		t.Fatalf("getSystemdServices: %v", err)
+	}
+	for _, s := range services {
+		if s.Name != "nginx.service" {
+			continue
+		}
+		// Duas leituras com 1 s de intervalo: ~25%.
+		if s.CPUUsage < 20 || s.CPUUsage > 26 {
+			t.Errorf("CPU do nginx = %.2f%%, esperado ~25%%", s.CPUUsage)
+		}
+		return
+	}
+	t.Error("nginx.service não listado")
+}
+
+func TestListUnitsSignature(t *testing.T) {
+	// Resposta crua do ListUnits, assinatura a(ssssssouso).
+	body := []interface{}{[][]interface{}{{
+		"cron.service", "Agendador", "loaded", "active", "running", "",
+		dbus.ObjectPath("/org/freedesktop/systemd1/unit/cron_2eservice"), uint32(0), "", dbus.ObjectPath("/"),
+	}}}
+
+	var units []systemdUnitStatus
+	if err := dbus.Store(body, &units); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if len(units) != 1 || units[0].Name != "cron.service" || units[0].SubState != "running" || units[0].Path != "/org/freedesktop/systemd1/unit/cron_2eservice" {
+		t.Errorf("unidade mal convertida: %+v", units)
+	}
+}
+
+func TestParseSystemctlUnits(t *testing.T) {
+	output := "● backup.service loaded failed failed Backup noturno\n  cron.service   loaded active running Regular background program processing daemon\n"
+	services := parseSystemctlUnits(output)
+	if len(services) != 2 {
+		t.Fatalf("esperados 2 serviços, veio %+v", services)
+	}
+	if services[0].Name != "backup.service" || !services[0].Failed || services[0].Description != "Backup noturno" {
+		t.Errorf("unidade com falha mal lida: %+v", services[0])
+	}
+	if services[1].SubState != "running" || services[1].Failed {
+		t.Errorf("cron mal lido: %+v", services[1])
+	}
+}
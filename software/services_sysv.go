@@ -0,0 +1,104 @@
+package software
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// sysvBackend roda o "status" de cada script em /etc/init.d. Habilitado é
+// ter um link S* em algum dos runlevels multiusuário (2 a 5).
+type sysvBackend struct {
+	root string
+}
+
+// sysvSkip são arquivos do /etc/init.d que não são serviços.
+var sysvSkip = map[string]bool{
+	"README":    true,
+	"skeleton":  true,
+	"rc":        true,
+	"rcS":       true,
+	"functions": true,
+	"halt":      true,
+	"reboot":    true,
+	"single":    true,
+	"killprocs": true,
+}
+
+const sysvStatusTimeout = 5 * time.Second
+
+func (b sysvBackend) Name() string { return "sysv" }
+
+func (b sysvBackend) Available() bool {
+	return fileExists(rootPath(b.root, "etc/init.d"))
+}
+
+func (b sysvBackend) List() ([]Service, error) {
+	dir := rootPath(b.root, "etc/init.d")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var services []Service
+	for _, entry := range entries {
+		name := entry.Name()
+		if sysvSkip[name] || strings.HasPrefix(name, ".") || strings.Contains(name, ".dpkg-") {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+
+		s := sysvStatus(filepath.Join(dir, name))
+		s.Name = name
+		s.Enabled = "disabled"
+		if links, _ := filepath.Glob(rootPath(b.root, "etc/rc[2-5].d/S[0-9][0-9]"+name)); len(links) > 0 {
+			s.Enabled = "enabled"
+		}
+		services = append(services, s)
+	}
+
+	return services, nil
+}
+
+// sysvStatus interpreta o código de saída do LSB: 0 rodando, 1 e 2 morto
+// com pidfile ou lockfile sobrando (falha), 3 parado. Scripts que nem
+// mencionam "status" não são executados, porque responderiam com o uso
+// e um código de erro qualquer.
+func sysvStatus(script string) Service {
+	s := Service{Status: "unknown"}
+
+	data, err := os.ReadFile(script)
+	if err != nil || !strings.Contains(string(data), "status") {
+		return s
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sysvStatusTimeout)
+	defer cancel()
+
+	err = exec.CommandContext(ctx, script, "status").Run()
+	code := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || ctx.Err() != nil {
+			return s
+		}
+		code = exitErr.ExitCode()
+	}
+
+	switch code {
+	case 0:
+		s.Status, s.SubState = "active", "running"
+	case 1, 2:
+		s.Status, s.SubState, s.Failed = "failed", "dead", true
+	case 3:
+		s.Status, s.SubState = "inactive", "stopped"
+	}
+	return s
+}
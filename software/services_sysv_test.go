@@ -0,0 +1,54 @@
+package software
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestSysvList(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("scripts de init precisam de /bin/sh")
+	}
+
+	root := t.TempDir()
+	script := func(code string) string {
+		return "#!/bin/sh\ncase \"$1\" in\n  status) exit " + code + " ;;\nesac\n"
+	}
+	writeRootFile(t, root, "etc/init.d/ssh", script("0"), 0755)
+	writeRootFile(t, root, "etc/init.d/cups", script("1"), 0755)
+	writeRootFile(t, root, "etc/init.d/lpd", script("2"), 0755)
+	writeRootFile(t, root, "etc/init.d/nfs", script("3"), 0755)
+	writeRootFile(t, root, "etc/init.d/exotico", script("4"), 0755)
+	// Sem "status" o script não é executado.
+	writeRootFile(t, root, "etc/init.d/hwclock", "#!/bin/sh\nexit 0\n", 0755)
+	writeRootFile(t, root, "etc/init.d/naoexec", script("0"), 0644)
+	writeRootFile(t, root, "etc/init.d/ssh.dpkg-old", script("0"), 0755)
+	writeRootFile(t, root, "etc/init.d/README", "status\n", 0755)
+	writeRootFile(t, root, "etc/init.d/skeleton", script("0"), 0755)
+
+	os.MkdirAll(filepath.Join(root, "etc/rc2.d"), 0755)
+	os.MkdirAll(filepath.Join(root, "etc/rc0.d"), 0755)
+	os.Symlink("../init.d/ssh", filepath.Join(root, "etc/rc2.d/S01ssh"))
+	os.Symlink("../init.d/nfs", filepath.Join(root, "etc/rc2.d/K01nfs"))
+	os.Symlink("../init.d/cups", filepath.Join(root, "etc/rc0.d/S02cups"))
+
+	services, err := sysvBackend{root: root}.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Service{
+		{Name: "cups", Status: "failed", SubState: "dead", Enabled: "disabled", Failed: true},
+		{Name: "exotico", Status: "unknown", Enabled: "disabled"},
+		{Name: "hwclock", Status: "unknown", Enabled: "disabled"},
+		{Name: "lpd", Status: "failed", SubState: "dead", Enabled: "disabled", Failed: true},
+		{Name: "nfs", Status: "inactive", SubState: "stopped", Enabled: "disabled"},
+		{Name: "ssh", Status: "active", SubState: "running", Enabled: "enabled"},
+	}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("serviços inesperados:\n obtido  %+v\n esperado %+v", services, want)
+	}
+}
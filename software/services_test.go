@@ -0,0 +1,71 @@
+package software
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// writeRootFile cria um arquivo dentro da raiz falsa, com os diretórios
+// que faltarem.
+func writeRootFile(t *testing.T, root, name, content string, perm os.FileMode) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetectServiceBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("backends de init só existem fora do Windows")
+	}
+
+	// Os comandos procurados no PATH existem, mas nada subiu por eles até
+	// que o diretório de cada init apareça na raiz.
+	bin := t.TempDir()
+	for _, name := range []string{"rc-status", "runsvdir", "s6-svstat"} {
+		writeRootFile(t, bin, name, "#!/bin/sh\n", 0755)
+	}
+	t.Setenv("PATH", bin)
+
+	root := t.TempDir()
+	steps := []struct {
+		add  string
+		want string
+	}{
+		{"", ""},
+		{"etc/init.d", "sysv"},
+		{"run/openrc", "openrc"},
+		{"etc/service", "runit"},
+		{"run/service", "s6"},
+		{"run/systemd/system", "systemd"},
+	}
+
+	for _, step := range steps {
+		if step.add != "" {
+			if err := os.MkdirAll(filepath.Join(root, step.add), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var got string
+		if backend := detectServiceBackend(newServiceBackends(root)); backend != nil {
+			got = backend.Name()
+		}
+		if got != step.want {
+			t.Errorf("com %q: esperado %q, obtido %q", step.add, step.want, got)
+		}
+	}
+
+	// Sem os comandos no PATH, os diretórios não bastam.
+	t.Setenv("PATH", t.TempDir())
+	os.RemoveAll(filepath.Join(root, "run/systemd"))
+	if backend := detectServiceBackend(newServiceBackends(root)); backend == nil || backend.Name() != "sysv" {
+		t.Errorf("esperado sysv sem os comandos dos outros inits, obtido %v", backend)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"monitoramento/alert"
+	"monitoramento/hardware"
+)
+
+type usbEventLog struct {
+	mu     sync.Mutex
+	events []hardware.USBEvent
+}
+
+func (l *usbEventLog) add(event hardware.USBEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *usbEventLog) drain() []hardware.USBEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	events := l.events
+	l.events = nil
+	return events
+}
+
+// newUSBPolicy falha com qualquer regra inválida: ignorar a allowlist por
+// causa de um erro de digitação liberaria todos os dispositivos.
+func newUSBPolicy(config map[string]string) (hardware.USBPolicy, error) {
+	var policy hardware.USBPolicy
+	var err error
+
+	policy.Allow, err = hardware.ParseUSBRules(config["usb_allowlist"])
+	if err != nil {
+		return hardware.USBPolicy{}, fmt.Errorf("erro na allowlist de USB: %v", err)
+	}
+
+	policy.Deny, err = hardware.ParseUSBRules(config["usb_denylist"])
+	if err != nil {
+		return hardware.USBPolicy{}, fmt.Errorf("erro na denylist de USB: %v", err)
+	}
+
+	return policy, nil
+}
+
+// usbQueueSize dá folga para rajadas de eventos enquanto um alerta
+// anterior ainda está sendo enviado (SMTP pode levar segundos).
+const usbQueueSize = 256
+
+func (a *agent) watchUSB() {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "Desconhecido"
+	}
+
+	events := make(chan hardware.USBEvent, usbQueueSize)
+	go func() {
+		if err := hardware.WatchUSB(a.usbPolicy, events); err != nil {
+			log.Printf("Erro ao monitorar dispositivos USB: %v", err)
+		}
+		close(events)
+	}()
+
+	// O envio fica fora do laço de leitura para não segurar o socket.
+	alerts := make(chan []alert.Alert, usbQueueSize)
+	defer close(alerts)
+	go func() {
+		for batch := range alerts {
+			if err := a.dispatcher.Dispatch(batch); err != nil {
+				log.Printf("Erro ao enviar alertas: %v", err)
+			}
+		}
+	}()
+
+	for event := range events {
+		log.Printf("USB %s: %s (%s:%s) na porta %s - %s", event.Action, event.Device.Name, event.Device.VendorID, event.Device.ProductID, event.Device.Path, event.Verdict)
+		a.usbEvents.add(event)
+
+		batch := alert.CheckUSBEvent(host, event)
+		if len(batch) == 0 {
+			continue
+		}
+		select {
+		case alerts <- batch:
+		default:
+			log.Printf("Fila de alertas de USB cheia, %d alerta(s) descartado(s)", len(batch))
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestNewUSBPolicy(t *testing.T) {
+	policy, err := newUSBPolicy(map[string]string{"usb_allowlist": "046d:*, class:03", "usb_denylist": "class:08"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Allow) != 2 || len(policy.Deny) != 1 {
+		t.Errorf("política inesperada: %+v", policy)
+	}
+
+	// Uma regra inválida não pode deixar a allowlist vazia, o que liberaria
+	// todos os dispositivos.
+	for _, config := range []map[string]string{
+		{"usb_allowlist": "046d:*, 0781-5567"},
+		{"usb_allowlist": "046d:*", "usb_denylist": "classe:08"},
+	} {
+		if _, err := newUSBPolicy(config); err == nil {
+			t.Errorf("esperado erro para %v", config)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CgroupRoot devolve a hierarquia v2: /sys/fs/cgroup no modo unificado ou
+// /sys/fs/cgroup/unified no modo híbrido. Sem cgroup v2 retorna "".
+func CgroupRoot(root string) string {
+	for _, dir := range []string{"sys/fs/cgroup", "sys/fs/cgroup/unified"} {
+		dir = filepath.Join(root, dir)
+		if _, err := os.Stat(filepath.Join(dir, "cgroup.controllers")); err == nil {
+			return dir
+		}
+	}
+	return ""
+}
+
+// ReadKeyValues lê arquivos no formato "chave valor" por linha, como
+// cpu.stat e memory.events.
+func ReadKeyValues(path string) map[string]uint64 {
+	values := make(map[string]uint64)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = v
+		}
+	}
+
+	return values
+}
+
+// ReadCgroupUint lê arquivos de um único número, como memory.current.
+func ReadCgroupUint(path string) (uint64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	return v, err == nil
+}
@@ -48,13 +48,26 @@ func EncryptJSON(jsonData []byte, hexKey string) (string, error) {
 	return encodedData, nil
 }
 
+// ReadINIFile retorna só as chaves globais, que ficam antes de qualquer
+// seção.
 func ReadINIFile(filename string) (map[string]string, error) {
+	sections, err := ReadINISections(filename)
+	if err != nil {
+		return nil, err
+	}
+	return sections[""], nil
+}
+
+// ReadINISections lê o arquivo inteiro separando as chaves por seção
+// ("[nome]"). As chaves globais ficam na seção "".
+func ReadINISections(filename string) (map[string]map[string]string, error) {
 	content, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	config := make(map[string]string)
+	sections := map[string]map[string]string{"": {}}
+	config := sections[""]
 	lines := strings.Split(string(content), "\n")
 
 	for _, line := range lines {
@@ -63,6 +76,15 @@ func ReadINIFile(filename string) (map[string]string, error) {
 			continue
 		}
 
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if sections[name] == nil {
+				sections[name] = make(map[string]string)
+			}
+			config = sections[name]
+			continue
+		}
+
 		parts := strings.SplitN(line, "=", 2)
 		if len(parts) != 2 {
 			continue
@@ -73,6 +95,6 @@ func ReadINIFile(filename string) (map[string]string, error) {
 		config[key] = value
 	}
 
-	return config, nil
+	return sections, nil
 }
 
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"log"
+
+	"monitoramento/vuln"
+)
+
+// newVulnDatabase carrega a base OSV local indicada em vuln_db_path; sem a
+// chave (ou com erro na leitura) o casamento de vulnerabilidades fica
+// desligado.
+func newVulnDatabase(config map[string]string) *vuln.Database {
+	path := config["vuln_db_path"]
+	if path == "" {
+		return nil
+	}
+
+	db, err := vuln.Load(path)
+	if err != nil {
+		log.Printf("Erro ao carregar base de vulnerabilidades: %v", err)
+		return nil
+	}
+	log.Printf("Base de vulnerabilidades carregada: %d pacotes afetados", db.Len())
+	return db
+}
@@ -0,0 +1,103 @@
+package vuln
+
+import (
+	"math"
+	"strings"
+)
+
+// cvss3Score calcula a nota base de um vetor CVSS v3.x
+// ("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"). Vetores de outras
+// versões ou incompletos devolvem false.
+func cvss3Score(vector string) (float64, bool) {
+	if !strings.HasPrefix(vector, "CVSS:3.") {
+		return 0, false
+	}
+
+	metrics := make(map[string]string)
+	for _, part := range strings.Split(vector, "/")[1:] {
+		key, value, ok := strings.Cut(part, ":")
+		if ok {
+			metrics[key] = value
+		}
+	}
+
+	weights := map[string]map[string]float64{
+		"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+		"AC": {"L": 0.77, "H": 0.44},
+		"UI": {"N": 0.85, "R": 0.62},
+		"C":  {"H": 0.56, "L": 0.22, "N": 0},
+		"I":  {"H": 0.56, "L": 0.22, "N": 0},
+		"A":  {"H": 0.56, "L": 0.22, "N": 0},
+	}
+	values := make(map[string]float64)
+	for key, options := range weights {
+		v, ok := options[metrics[key]]
+		if !ok {
+			return 0, false
+		}
+		values[key] = v
+	}
+
+	changed := metrics["S"] == "C"
+	if !changed && metrics["S"] != "U" {
+		return 0, false
+	}
+
+	// PR pesa diferente quando o escopo muda.
+	var pr float64
+	switch metrics["PR"] {
+	case "N":
+		pr = 0.85
+	case "L":
+		pr = 0.62
+		if changed {
+			pr = 0.68
+		}
+	case "H":
+		pr = 0.27
+		if changed {
+			pr = 0.5
+		}
+	default:
+		return 0, false
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+
+	exploitability := 8.22 * values["AV"] * values["AC"] * pr * values["UI"]
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// roundUp é o arredondamento para cima de uma casa definido na
+// especificação 3.1, que evita erros de ponto flutuante.
+func roundUp(x float64) float64 {
+	i := int64(math.Round(x * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
+
+func severityFromScore(score float64) string {
+	switch {
+	case score >= 9:
+		return "CRITICAL"
+	case score >= 7:
+		return "HIGH"
+	case score >= 4:
+		return "MEDIUM"
+	case score > 0:
+		return "LOW"
+	}
+	return "NONE"
+}
@@ -0,0 +1,344 @@
+package vuln
+
+import (
+	"sort"
+	"strings"
+
+	"monitoramento/software"
+)
+
+type Finding struct {
+	ID               string  `json:"id"`
+	CVE              string  `json:"cve,omitempty"`
+	Severity         string  `json:"severity"`
+	Score            float64 `json:"score,omitempty"`
+	Package          string  `json:"package"`
+	InstalledVersion string  `json:"installed_version"`
+	FixedVersion     string  `json:"fixed_version,omitempty"`
+	Ecosystem        string  `json:"ecosystem"`
+	Summary          string  `json:"summary,omitempty"`
+}
+
+// target é um pacote instalado já traduzido para os termos do OSV.
+type target struct {
+	name      string // nome exibido no achado
+	lookup    string // nome procurado na base (pacote fonte no Debian)
+	version   string
+	ecosystem string // "Debian", "PyPI", ...
+	release   string // versão da distribuição, vazia fora dos pacotes do SO
+	compare   func(a, b string) int
+}
+
+// Match cruza os pacotes do sistema e dos ecossistemas de linguagem com a
+// base. Pacotes de gerenciadores sem equivalente no OSV (snap, flatpak,
+// pacman, Windows) e distribuições não reconhecidas são ignorados.
+func (db *Database) Match(info software.Info) []Finding {
+	var targets []target
+
+	if ecosystem, compare := distroEcosystem(info.OS.Name); ecosystem != "" {
+		for _, app := range info.InstalledApps {
+			if app.Manager != distroManager[ecosystem] {
+				continue
+			}
+			lookup := app.Name
+			if app.SourcePackage != "" {
+				lookup = app.SourcePackage
+			}
+			targets = append(targets, target{
+				name:      app.Name,
+				lookup:    lookup,
+				version:   app.Version,
+				ecosystem: ecosystem,
+				release:   info.OS.Version,
+				compare:   compare,
+			})
+		}
+	}
+
+	for _, pkg := range info.EcosystemPackages {
+		ecosystem, ok := languageEcosystems[pkg.Ecosystem]
+		if !ok {
+			continue
+		}
+		compare := compareGeneric
+		if ecosystem == "Go" || ecosystem == "npm" {
+			compare = compareSemver
+		}
+		targets = append(targets, target{
+			name:      pkg.Name,
+			lookup:    pkg.Name,
+			version:   pkg.Version,
+			ecosystem: ecosystem,
+			compare:   compare,
+		})
+	}
+
+	var findings []Finding
+	seen := make(map[string]bool)
+	for _, t := range targets {
+		if t.version == "" || t.version == "(devel)" {
+			continue
+		}
+		for _, e := range db.byName[normalizeName(t.lookup)] {
+			if e.advisory.Withdrawn != "" || !ecosystemMatches(e.affected.Package.Ecosystem, t.ecosystem, t.release) {
+				continue
+			}
+			affected, fixed := isAffected(e.affected, t)
+			if !affected {
+				continue
+			}
+
+			key := e.advisory.ID + "|" + t.name + "|" + t.version
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			severity, score := severityOf(e.advisory, e.affected)
+			findings = append(findings, Finding{
+				ID:               e.advisory.ID,
+				CVE:              cveOf(e.advisory),
+				Severity:         severity,
+				Score:            score,
+				Package:          t.name,
+				InstalledVersion: t.version,
+				FixedVersion:     fixed,
+				Ecosystem:        e.affected.Package.Ecosystem,
+				Summary:          e.advisory.Summary,
+			})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		ri, rj := severityRank[findings[i].Severity], severityRank[findings[j].Severity]
+		if ri != rj {
+			return ri > rj
+		}
+		if findings[i].Package != findings[j].Package {
+			return findings[i].Package < findings[j].Package
+		}
+		if findings[i].ID != findings[j].ID {
+			return findings[i].ID < findings[j].ID
+		}
+		return findings[i].InstalledVersion < findings[j].InstalledVersion
+	})
+	return findings
+}
+
+var languageEcosystems = map[string]string{
+	"pypi":  "PyPI",
+	"npm":   "npm",
+	"go":    "Go",
+	"maven": "Maven",
+}
+
+var distroManager = map[string]string{
+	"Debian":      "deb",
+	"Ubuntu":      "deb",
+	"Alpine":      "apk",
+	"Red Hat":     "rpm",
+	"Rocky Linux": "rpm",
+	"AlmaLinux":   "rpm",
+	"SUSE":        "rpm",
+	"openSUSE":    "rpm",
+}
+
+// distroEcosystem traduz a plataforma informada pelo gopsutil para o
+// ecossistema OSV e a comparação de versões da distribuição.
+func distroEcosystem(platform string) (string, func(a, b string) int) {
+	platform = strings.ToLower(platform)
+	switch {
+	case platform == "debian" || platform == "raspbian":
+		return "Debian", compareDpkg
+	case platform == "ubuntu":
+		return "Ubuntu", compareDpkg
+	case platform == "alpine":
+		return "Alpine", compareAPK
+	case platform == "redhat" || platform == "rhel":
+		return "Red Hat", compareRPM
+	case platform == "rocky":
+		return "Rocky Linux", compareRPM
+	case platform == "almalinux":
+		return "AlmaLinux", compareRPM
+	case strings.HasPrefix(platform, "opensuse"):
+		return "openSUSE", compareRPM
+	case platform == "sles" || platform == "suse":
+		return "SUSE", compareRPM
+	}
+	return "", nil
+}
+
+// ecosystemMatches compara o ecossistema do aviso ("Debian:12",
+// "Alpine:v3.18", "Ubuntu:22.04:LTS", "Red Hat:enterprise_linux:9::appstream")
+// com o do pacote. Se o aviso indica release, uma das partes precisa bater
+// com a versão do sistema inteira, só o major ou major.minor.
+func ecosystemMatches(advisory, ecosystem, release string) bool {
+	parts := strings.Split(advisory, ":")
+	if !strings.EqualFold(parts[0], ecosystem) {
+		return false
+	}
+	if len(parts) == 1 || release == "" {
+		return true
+	}
+
+	numbers := strings.Split(release, ".")
+	candidates := []string{release, numbers[0]}
+	if len(numbers) > 1 {
+		candidates = append(candidates, numbers[0]+"."+numbers[1])
+	}
+
+	for _, part := range parts[1:] {
+		part = strings.TrimPrefix(part, "v")
+		for _, c := range candidates {
+			if part == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isAffected avalia os intervalos do aviso e, quando afetado, devolve a
+// versão que corrige (vazia se ainda não há correção).
+func isAffected(affected *Affected, t target) (bool, string) {
+	for _, r := range affected.Ranges {
+		compare := t.compare
+		switch r.Type {
+		case "SEMVER":
+			compare = compareSemver
+		case "ECOSYSTEM":
+		default:
+			continue
+		}
+		if ok, fixed := inRange(r.Events, t.version, compare); ok {
+			return true, fixed
+		}
+	}
+
+	for _, v := range affected.Versions {
+		if t.compare(v, t.version) == 0 {
+			return true, ""
+		}
+	}
+	return false, ""
+}
+
+// inRange percorre os eventos em ordem de versão: cada "introduced" abre
+// um intervalo e o "fixed" ou "last_affected" seguinte o fecha. Versões a
+// partir de um "limit" ficam fora de todos os intervalos.
+func inRange(events []Event, version string, compare func(a, b string) int) (bool, string) {
+	var ordered []Event
+	for _, e := range events {
+		if e.Limit == "" {
+			ordered = append(ordered, e)
+		} else if e.Limit != "*" && compare(version, e.Limit) >= 0 {
+			return false, ""
+		}
+	}
+	events = ordered
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := eventVersion(events[i]), eventVersion(events[j])
+		if a == "0" || b == "0" {
+			return a == "0" && b != "0"
+		}
+		return compare(a, b) < 0
+	})
+
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" || compare(version, e.Introduced) >= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if compare(version, e.Fixed) < 0 {
+				if affected {
+					return true, e.Fixed
+				}
+			} else {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if compare(version, e.LastAffected) <= 0 {
+				if affected {
+					return true, ""
+				}
+			} else {
+				affected = false
+			}
+		}
+	}
+	return affected, ""
+}
+
+func eventVersion(e Event) string {
+	return firstNonEmpty(e.Introduced, e.Fixed, e.LastAffected)
+}
+
+var severityRank = map[string]int{
+	"UNKNOWN":  0,
+	"NONE":     0,
+	"LOW":      1,
+	"MEDIUM":   2,
+	"HIGH":     3,
+	"CRITICAL": 4,
+}
+
+// severityOf prefere a classificação da própria base e cai para a nota
+// calculada do vetor CVSS v3; a urgência do Debian/Ubuntu é o último recurso.
+func severityOf(advisory *Advisory, affected *Affected) (string, float64) {
+	var score float64
+	for _, list := range [][]Severity{affected.Severity, advisory.Severity} {
+		for _, s := range list {
+			if v, ok := cvss3Score(s.Score); ok && score == 0 {
+				score = v
+			}
+		}
+	}
+
+	severity := strings.ToUpper(firstNonEmpty(
+		affected.DatabaseSpecific.Severity,
+		affected.EcosystemSpecific.Severity,
+		advisory.DatabaseSpecific.Severity,
+	))
+	switch severity {
+	case "MODERATE":
+		severity = "MEDIUM"
+	case "IMPORTANT":
+		severity = "HIGH"
+	}
+	if _, ok := severityRank[severity]; ok {
+		return severity, score
+	}
+
+	if score > 0 {
+		return severityFromScore(score), score
+	}
+
+	urgency := strings.ToUpper(firstNonEmpty(affected.EcosystemSpecific.Urgency, affected.DatabaseSpecific.Urgency))
+	if _, ok := severityRank[urgency]; ok {
+		return urgency, score
+	}
+	return "UNKNOWN", score
+}
+
+// cveOf devolve o primeiro CVE entre o ID e os aliases; bases de
+// distribuição usam IDs como "DEBIAN-CVE-2024-1234".
+func cveOf(advisory *Advisory) string {
+	for _, id := range append([]string{advisory.ID}, advisory.Aliases...) {
+		if i := strings.Index(id, "CVE-"); i >= 0 {
+			return id[i:]
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,207 @@
+package vuln
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"monitoramento/software"
+)
+
+var testAdvisories = []string{
+	`{
+  "id": "DEBIAN-CVE-2023-5678",
+  "affected": [
+    {
+      "package": {"ecosystem": "Debian:12", "name": "openssl"},
+      "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.11-1~deb12u2"}]}],
+      "ecosystem_specific": {"urgency": "medium"}
+    },
+    {
+      "package": {"ecosystem": "Debian:12", "name": "openssl"},
+      "versions": ["3.0.9-1"]
+    }
+  ]
+}`,
+	`{
+  "id": "DEBIAN-CVE-2022-0001",
+  "affected": [{
+    "package": {"ecosystem": "Debian:11", "name": "openssl"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "9.9.9-1"}]}]
+  }]
+}`,
+	`{
+  "id": "GO-2024-2687",
+  "aliases": ["CVE-2023-45288"],
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "stdlib"},
+    "ranges": [{"type": "SEMVER", "events": [
+      {"introduced": "0"}, {"fixed": "1.21.9"},
+      {"introduced": "1.22.0-0"}, {"fixed": "1.22.2"}
+    ]}]
+  }]
+}`,
+	`{
+  "id": "GHSA-wxyz",
+  "withdrawn": "2024-01-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "stdlib"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+  }]
+}`,
+	`{
+  "id": "PYSEC-2023-74",
+  "affected": [{
+    "package": {"ecosystem": "PyPI", "name": "requests"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.3.0"}, {"fixed": "2.31.0"}]}],
+    "database_specific": {"severity": "MODERATE"}
+  }]
+}`,
+}
+
+func testDatabase(t *testing.T) *Database {
+	t.Helper()
+	db := &Database{byName: make(map[string][]entry)}
+	for i, advisory := range testAdvisories {
+		if err := db.add(strings.NewReader(advisory), "aviso"); err != nil {
+			t.Fatalf("aviso %d: %v", i, err)
+		}
+	}
+	return db
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name string
+		info software.Info
+		want []Finding
+	}{
+		{
+			name: "pacote binário do Debian pelo pacote fonte",
+			info: software.Info{
+				OS: software.OSInfo{Name: "debian", Version: "12.5"},
+				InstalledApps: []software.InstalledApp{
+					{Name: "libssl3", Version: "3.0.9-1", SourcePackage: "openssl", Manager: "deb"},
+					{Name: "openssl", Version: "3.0.11-1~deb12u2", SourcePackage: "openssl", Manager: "deb"},
+					{Name: "python3-requests", Version: "2.28.1", Manager: "deb"},
+				},
+			},
+			want: []Finding{
+				{ID: "DEBIAN-CVE-2023-5678", CVE: "CVE-2023-5678", Severity: "MEDIUM", Package: "libssl3", InstalledVersion: "3.0.9-1", FixedVersion: "3.0.11-1~deb12u2", Ecosystem: "Debian:12"},
+			},
+		},
+		{
+			name: "aviso de outra versão da distribuição",
+			info: software.Info{
+				OS:            software.OSInfo{Name: "debian", Version: "11.9"},
+				InstalledApps: []software.InstalledApp{{Name: "libssl3", Version: "3.0.9-1", SourcePackage: "openssl", Manager: "deb"}},
+			},
+			want: []Finding{
+				{ID: "DEBIAN-CVE-2022-0001", CVE: "CVE-2022-0001", Severity: "UNKNOWN", Package: "libssl3", InstalledVersion: "3.0.9-1", FixedVersion: "9.9.9-1", Ecosystem: "Debian:11"},
+			},
+		},
+		{
+			name: "distribuição sem ecossistema no OSV",
+			info: software.Info{
+				OS:            software.OSInfo{Name: "arch", Version: "rolling"},
+				InstalledApps: []software.InstalledApp{{Name: "openssl", Version: "3.0.9-1", Manager: "pacman"}},
+			},
+		},
+		{
+			name: "Go stdlib com e sem o prefixo go, sem duplicar binários",
+			info: software.Info{
+				EcosystemPackages: []software.EcosystemPackage{
+					{Ecosystem: "go", Name: "stdlib", Version: "go1.22.1", Location: "/usr/local/bin/a"},
+					{Ecosystem: "go", Name: "stdlib", Version: "go1.22.1", Location: "/usr/local/bin/b"},
+					{Ecosystem: "go", Name: "stdlib", Version: "1.21.5", Location: "/usr/local/bin/c"},
+					{Ecosystem: "go", Name: "stdlib", Version: "go1.22.2", Location: "/usr/local/bin/d"},
+					{Ecosystem: "go", Name: "stdlib", Version: "go1.21.9 X:boringcrypto", Location: "/usr/local/bin/e"},
+					{Ecosystem: "go", Name: "stdlib", Version: "go1.21.8 X:boringcrypto", Location: "/usr/local/bin/f"},
+					{Ecosystem: "go", Name: "example.com/tool", Version: "(devel)", Location: "/usr/local/bin/a"},
+				},
+			},
+			want: []Finding{
+				{ID: "GO-2024-2687", CVE: "CVE-2023-45288", Severity: "UNKNOWN", Package: "stdlib", InstalledVersion: "1.21.5", FixedVersion: "1.21.9", Ecosystem: "Go"},
+				{ID: "GO-2024-2687", CVE: "CVE-2023-45288", Severity: "UNKNOWN", Package: "stdlib", InstalledVersion: "go1.21.8 X:boringcrypto", FixedVersion: "1.21.9", Ecosystem: "Go"},
+				{ID: "GO-2024-2687", CVE: "CVE-2023-45288", Severity: "UNKNOWN", Package: "stdlib", InstalledVersion: "go1.22.1", FixedVersion: "1.22.2", Ecosystem: "Go"},
+			},
+		},
+		{
+			name: "nome normalizado do PyPI",
+			info: software.Info{
+				EcosystemPackages: []software.EcosystemPackage{
+					{Ecosystem: "pypi", Name: "Requests", Version: "2.28.1"},
+					{Ecosystem: "pypi", Name: "requests", Version: "2.31.0"},
+				},
+			},
+			want: []Finding{
+				{ID: "PYSEC-2023-74", Severity: "MEDIUM", Package: "Requests", InstalledVersion: "2.28.1", FixedVersion: "2.31.0", Ecosystem: "PyPI"},
+			},
+		},
+	}
+
+	db := testDatabase(t)
+	for _, tt := range tests {
+		if got := db.Match(tt.info); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n obtido  %+v\n esperado %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    []Event
+		version   string
+		want      bool
+		wantFixed string
+	}{
+		{"desde o início", []Event{{Introduced: "0"}, {Fixed: "1.5"}}, "1.0", true, "1.5"},
+		{"versão corrigida", []Event{{Introduced: "0"}, {Fixed: "1.5"}}, "1.5", false, ""},
+		{"antes de introduzido", []Event{{Introduced: "1.2"}, {Fixed: "1.5"}}, "1.1", false, ""},
+		{"sem correção", []Event{{Introduced: "1.2"}}, "7.0", true, ""},
+		{"segundo intervalo", []Event{{Introduced: "1.0"}, {Fixed: "1.5"}, {Introduced: "2.0"}, {Fixed: "2.3"}}, "2.1", true, "2.3"},
+		{"entre intervalos", []Event{{Introduced: "1.0"}, {Fixed: "1.5"}, {Introduced: "2.0"}, {Fixed: "2.3"}}, "1.8", false, ""},
+		{"eventos fora de ordem", []Event{{Fixed: "2.3"}, {Introduced: "2.0"}, {Fixed: "1.5"}, {Introduced: "1.0"}}, "1.2", true, "1.5"},
+		{"last_affected incluído", []Event{{Introduced: "0"}, {LastAffected: "3.1"}}, "3.1", true, ""},
+		{"depois de last_affected", []Event{{Introduced: "0"}, {LastAffected: "3.1"}}, "3.1.1", false, ""},
+		{"abaixo do limit", []Event{{Introduced: "1.0"}, {Limit: "2.0"}}, "1.9", true, ""},
+		{"no limit", []Event{{Introduced: "1.0"}, {Limit: "2.0"}}, "2.0", false, ""},
+		{"limit acima de uma reintrodução", []Event{{Introduced: "0"}, {Fixed: "1.5"}, {Introduced: "3.0"}, {Limit: "2.5"}}, "3.1", false, ""},
+	}
+
+	for _, tt := range tests {
+		got, fixed := inRange(tt.events, tt.version, compareGeneric)
+		if got != tt.want || fixed != tt.wantFixed {
+			t.Errorf("%s: inRange(%s) = %v, %q; esperado %v, %q", tt.name, tt.version, got, fixed, tt.want, tt.wantFixed)
+		}
+	}
+}
+
+func TestEcosystemMatches(t *testing.T) {
+	tests := []struct {
+		advisory, ecosystem, release string
+		want                         bool
+	}{
+		{"Debian:12", "Debian", "12", true},
+		{"Debian:12", "Debian", "12.5", true},
+		{"Debian:11", "Debian", "12.5", false},
+		{"Debian", "Debian", "12", true},
+		{"Alpine:v3.18", "Alpine", "3.18.4", true},
+		{"Alpine:v3.19", "Alpine", "3.18.4", false},
+		{"Ubuntu:22.04:LTS", "Ubuntu", "22.04", true},
+		{"Ubuntu:Pro:18.04:LTS", "Ubuntu", "22.04", false},
+		{"Red Hat:enterprise_linux:9::appstream", "Red Hat", "9.3", true},
+		{"Red Hat:enterprise_linux:8::baseos", "Red Hat", "9.3", false},
+		{"PyPI", "PyPI", "", true},
+		{"pypi", "PyPI", "", true},
+		{"npm", "PyPI", "", false},
+		{"Debian:12", "Ubuntu", "12", false},
+	}
+
+	for _, tt := range tests {
+		if got := ecosystemMatches(tt.advisory, tt.ecosystem, tt.release); got != tt.want {
+			t.Errorf("ecosystemMatches(%q, %q, %q) = %v, esperado %v", tt.advisory, tt.ecosystem, tt.release, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package vuln
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Advisory é o subconjunto do formato OSV (https://ossf.github.io/osv-schema/)
+// usado no casamento com os pacotes instalados.
+type Advisory struct {
+	ID               string           `json:"id"`
+	Aliases          []string         `json:"aliases"`
+	Summary          string           `json:"summary"`
+	Withdrawn        string           `json:"withdrawn"`
+	Severity         []Severity       `json:"severity"`
+	Affected         []Affected       `json:"affected"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+}
+
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type Affected struct {
+	Package           AffectedPackage  `json:"package"`
+	Ranges            []Range          `json:"ranges"`
+	Versions          []string         `json:"versions"`
+	Severity          []Severity       `json:"severity"`
+	EcosystemSpecific DatabaseSpecific `json:"ecosystem_specific"`
+	DatabaseSpecific  DatabaseSpecific `json:"database_specific"`
+}
+
+type AffectedPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// DatabaseSpecific guarda os campos livres que algumas bases usam para a
+// severidade (GHSA usa "severity", Debian e Ubuntu usam "urgency").
+type DatabaseSpecific struct {
+	Severity string `json:"severity"`
+	Urgency  string `json:"urgency"`
+}
+
+// Database indexa os avisos pelo nome do pacote afetado.
+type Database struct {
+	byName map[string][]entry
+}
+
+type entry struct {
+	advisory *Advisory
+	affected *Affected
+}
+
+// Load lê a base local: um diretório com arquivos .json (um aviso por
+// arquivo, como no bucket do OSV) e/ou os .zip de exportação por
+// ecossistema. path também pode apontar direto para um arquivo. Avisos
+// ilegíveis ou malformados são registrados no log e ignorados; só falha
+// se o próprio path não puder ser lido.
+func Load(path string) (*Database, error) {
+	db := &Database{byName: make(map[string][]entry)}
+
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == path {
+				return err
+			}
+			log.Printf("Erro ao ler %s, ignorado: %v", p, err)
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".json":
+			if err := db.addFile(p); err != nil {
+				log.Printf("Erro ao carregar aviso de vulnerabilidade, ignorado: %v", err)
+			}
+		case ".zip":
+			if err := db.addZip(p); err != nil {
+				log.Printf("Erro ao abrir %s, ignorado: %v", p, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar base de vulnerabilidades: %v", err)
+	}
+
+	return db, nil
+}
+
+func (db *Database) addFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return db.add(file, path)
+}
+
+func (db *Database) addZip(path string) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for _, f := range archive.File {
+		if !strings.HasSuffix(strings.ToLower(f.Name), ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			log.Printf("Erro ao ler %s:%s, ignorado: %v", path, f.Name, err)
+			continue
+		}
+		err = db.add(rc, path+":"+f.Name)
+		rc.Close()
+		if err != nil {
+			log.Printf("Erro ao carregar aviso de vulnerabilidade, ignorado: %v", err)
+		}
+	}
+	return nil
+}
+
+func (db *Database) add(r io.Reader, name string) error {
+	var advisory Advisory
+	if err := json.NewDecoder(r).Decode(&advisory); err != nil {
+		return fmt.Errorf("erro ao interpretar %s: %v", name, err)
+	}
+
+	for i := range advisory.Affected {
+		affected := &advisory.Affected[i]
+		key := normalizeName(affected.Package.Name)
+		db.byName[key] = append(db.byName[key], entry{advisory: &advisory, affected: affected})
+	}
+	return nil
+}
+
+// normalizeName deixa o nome em minúsculas e troca "_" e "." por "-",
+// como o PyPI faz; nos demais ecossistemas isso só afeta o índice.
+func normalizeName(name string) string {
+	return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+}
+
+// Len devolve o número de pares aviso/pacote carregados.
+func (db *Database) Len() int {
+	n := 0
+	for _, entries := range db.byName {
+		n += len(entries)
+	}
+	return n
+}
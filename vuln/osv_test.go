@@ -0,0 +1,62 @@
+package vuln
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testAdvisory = `{
+  "id": "DEBIAN-CVE-2023-5678",
+  "affected": [{
+    "package": {"ecosystem": "Debian:12", "name": "openssl"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.11-1~deb12u2"}]}]
+  }]
+}`
+
+func TestLoadSkipsMalformedAdvisories(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "good.json"), testAdvisory)
+	writeFile(t, filepath.Join(dir, "bad.json"), `{"id": "GHSA-quebrado", "affected": [`)
+	writeFile(t, filepath.Join(dir, "broken.zip"), "isto não é um zip")
+
+	archive, err := os.Create(filepath.Join(dir, "all.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(archive)
+	for name, content := range map[string]string{
+		"PYSEC-1.json": `{"id": "PYSEC-1", "affected": [{"package": {"ecosystem": "PyPI", "name": "requests"}}]}`,
+		"PYSEC-2.json": `não é json`,
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	archive.Close()
+
+	db, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load falhou: %v", err)
+	}
+	if db.Len() != 2 {
+		t.Errorf("Len() = %d, esperado 2 (openssl e requests)", db.Len())
+	}
+}
+
+func TestLoadMissingPath(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "nao-existe")); err == nil {
+		t.Error("esperado erro para caminho inexistente")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,488 @@
+package vuln
+
+import (
+	"strconv"
+	"strings"
+)
+
+// compareDpkg segue o algoritmo do dpkg: epoch numérico, depois a versão
+// upstream e a revisão Debian, onde "~" ordena antes de tudo (inclusive do
+// fim da string) e letras antes dos demais símbolos.
+func compareDpkg(a, b string) int {
+	ea, ua, ra := splitDpkg(a)
+	eb, ub, rb := splitDpkg(b)
+
+	if c := compareNumeric(ea, eb); c != 0 {
+		return c
+	}
+	if c := dpkgVerRevCmp(ua, ub); c != 0 {
+		return c
+	}
+	return dpkgVerRevCmp(ra, rb)
+}
+
+func splitDpkg(v string) (epoch, upstream, revision string) {
+	epoch = "0"
+	if i := strings.IndexByte(v, ':'); i >= 0 {
+		epoch, v = v[:i], v[i+1:]
+	}
+	if i := strings.LastIndexByte(v, '-'); i >= 0 {
+		return epoch, v[:i], v[i+1:]
+	}
+	return epoch, v, ""
+}
+
+func dpkgOrder(s string) int {
+	switch {
+	case s == "":
+		return 0
+	case isDigit(s[0]):
+		return 0
+	case isAlpha(s[0]):
+		return int(s[0])
+	case s[0] == '~':
+		return -1
+	default:
+		return int(s[0]) + 256
+	}
+}
+
+func dpkgVerRevCmp(a, b string) int {
+	for a != "" || b != "" {
+		for (a != "" && !isDigit(a[0])) || (b != "" && !isDigit(b[0])) {
+			ac, bc := dpkgOrder(a), dpkgOrder(b)
+			if ac != bc {
+				return sign(ac - bc)
+			}
+			a, b = a[min(1, len(a)):], b[min(1, len(b)):]
+		}
+
+		var na, nb string
+		na, a = takeDigits(a)
+		nb, b = takeDigits(b)
+		if c := compareNumeric(na, nb); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareRPM compara EVRs ("epoch:versão-release") como o rpm: epoch
+// ausente vale 0 e a release só conta quando as duas versões têm uma.
+func compareRPM(a, b string) int {
+	ea, va, ra := splitDpkg(a)
+	eb, vb, rb := splitDpkg(b)
+
+	if c := compareNumeric(ea, eb); c != 0 {
+		return c
+	}
+	if c := rpmVerCmp(va, vb); c != 0 {
+		return c
+	}
+	if ra == "" || rb == "" {
+		return 0
+	}
+	return rpmVerCmp(ra, rb)
+}
+
+// rpmVerCmp é o rpmvercmp: segmentos numéricos e alfabéticos comparados um
+// a um, numérico ganha de alfabético, "~" ordena antes e "^" depois do fim.
+func rpmVerCmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	for {
+		a = strings.TrimLeftFunc(a, rpmSeparator)
+		b = strings.TrimLeftFunc(b, rpmSeparator)
+
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			if a == "" {
+				return -1
+			}
+			if b == "" {
+				return 1
+			}
+			if !strings.HasPrefix(a, "^") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "^") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		var sa, sb string
+		if isDigit(a[0]) {
+			sa, a = takeDigits(a)
+			sb, b = takeDigits(b)
+			if sb == "" {
+				return 1
+			}
+			if c := compareNumeric(sa, sb); c != 0 {
+				return c
+			}
+		} else {
+			sa, a = takeAlpha(a)
+			sb, b = takeAlpha(b)
+			if sb == "" {
+				return -1
+			}
+			if c := strings.Compare(sa, sb); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+func rpmSeparator(r rune) bool {
+	return r < 128 && !isDigit(byte(r)) && !isAlpha(byte(r)) && r != '~' && r != '^'
+}
+
+// compareSemver aceita os prefixos "v" (módulos Go) e "go" (versão do Go);
+// partes que faltam valem 0 e pré-releases ordenam antes da versão final.
+func compareSemver(a, b string) int {
+	a, preA := splitSemver(a)
+	b, preB := splitSemver(b)
+
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y string
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if c := compareNumeric(x, y); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+
+	ia, ib := strings.Split(preA, "."), strings.Split(preB, ".")
+	for i := 0; i < min(len(ia), len(ib)); i++ {
+		_, errA := strconv.Atoi(ia[i])
+		_, errB := strconv.Atoi(ib[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = compareNumeric(ia[i], ib[i])
+		case errA == nil:
+			c = -1
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(ia[i], ib[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return sign(len(ia) - len(ib))
+}
+
+// splitSemver também descarta o sufixo de GOEXPERIMENT que binários Go
+// trazem na versão ("go1.21.5 X:boringcrypto").
+func splitSemver(v string) (version, prerelease string) {
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "go"), "v")
+	v, _, _ = strings.Cut(v, " ")
+	v, _, _ = strings.Cut(v, "+")
+	version, prerelease, _ = strings.Cut(v, "-")
+	return version, prerelease
+}
+
+// compareGeneric é usado nos ecossistemas sem regra própria (PyPI, Maven):
+// quebra a versão em trechos numéricos e alfabéticos, tratando qualquer
+// outro caractere como separador, e compara trecho a trecho. Qualificadores
+// conhecidos seguem a ordem dev < alpha < beta < milestone < rc < snapshot
+// < versão final < post/patch. Não cobre todos os casos do PEP 440 ou do
+// Maven, mas acerta os comuns.
+func compareGeneric(a, b string) int {
+	ta, tb := versionTokens(a), versionTokens(b)
+
+	for i := 0; i < max(len(ta), len(tb)); i++ {
+		var x, y string
+		if i < len(ta) {
+			x = ta[i]
+		}
+		if i < len(tb) {
+			y = tb[i]
+		}
+
+		var c int
+		switch {
+		case x == "" && y == "":
+			c = 0
+		case isNumericToken(x) && isNumericToken(y):
+			c = compareNumeric(x, y)
+		case isNumericToken(x) && y == "":
+			c = compareNumeric(x, "0")
+		case x == "" && isNumericToken(y):
+			c = compareNumeric("0", y)
+		case isNumericToken(x):
+			// Número ganha de qualificador: 1.0.1 > 1.0rc1 e 1.0.1 > 1.0.post1.
+			c = 1
+		case isNumericToken(y):
+			c = -1
+		default:
+			c = compareQualifiers(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// versionTokens separa "1.0rc1 (r12)" em ["1" "0" "rc" "1" "r" "12"].
+func versionTokens(v string) []string {
+	var tokens []string
+	for v != "" {
+		var token string
+		switch {
+		case isDigit(v[0]):
+			token, v = takeDigits(v)
+		case isAlpha(v[0]):
+			token, v = takeAlpha(v)
+		default:
+			v = v[1:]
+			continue
+		}
+		tokens = append(tokens, strings.ToLower(token))
+	}
+	return tokens
+}
+
+func isNumericToken(token string) bool {
+	return token != "" && isDigit(token[0])
+}
+
+var qualifierRanks = map[string]int{
+	"dev":       -6,
+	"alpha":     -5,
+	"a":         -5,
+	"beta":      -4,
+	"b":         -4,
+	"milestone": -3,
+	"m":         -3,
+	"rc":        -2,
+	"cr":        -2,
+	"c":         -2,
+	"pre":       -2,
+	"preview":   -2,
+	"snapshot":  -1,
+	"":          0,
+	"final":     0,
+	"ga":        0,
+	"release":   0,
+	"post":      1,
+	"p":         1,
+	"pl":        1,
+	"patch":     1,
+	"sp":        1,
+	"r":         1,
+	"rev":       1,
+}
+
+// compareQualifiers ordena pelos ranks acima; palavras desconhecidas ficam
+// depois da versão final e entre si em ordem alfabética.
+func compareQualifiers(x, y string) int {
+	rx, okx := qualifierRanks[x]
+	ry, oky := qualifierRanks[y]
+	if !okx {
+		rx = 1
+	}
+	if !oky {
+		ry = 1
+	}
+	if rx != ry {
+		return sign(rx - ry)
+	}
+	if okx && oky {
+		return 0
+	}
+	return strings.Compare(x, y)
+}
+
+// apkVersion segue o formato do apk-tools: números separados por ponto,
+// uma letra opcional, sufixos "_<nome><número>" e a release "-r<número>".
+type apkVersion struct {
+	numbers  []string
+	letter   string
+	suffixes []apkSuffix
+	release  string
+}
+
+type apkSuffix struct {
+	rank   int
+	number string
+}
+
+// apkSuffixRanks: os de pré-lançamento ficam antes da versão sem sufixo e
+// os de snapshot/patch depois.
+var apkSuffixRanks = map[string]int{
+	"alpha": -4,
+	"beta":  -3,
+	"pre":   -2,
+	"rc":    -1,
+	"cvs":   1,
+	"svn":   2,
+	"git":   3,
+	"hg":    4,
+	"p":     5,
+}
+
+func parseAPKVersion(v string) (apkVersion, bool) {
+	var av apkVersion
+
+	if i := strings.LastIndex(v, "-r"); i >= 0 {
+		av.release = v[i+2:]
+		if av.release == "" || strings.TrimLeft(av.release, "0123456789") != "" {
+			return av, false
+		}
+		v = v[:i]
+	}
+
+	base, suffixes, _ := strings.Cut(v, "_")
+	for _, part := range strings.Split(base, ".") {
+		number, rest := takeDigits(part)
+		if number == "" {
+			return av, false
+		}
+		av.numbers = append(av.numbers, number)
+		if rest != "" {
+			// Só o último componente pode ter letra (1.2.3a).
+			if len(rest) != 1 || !isAlpha(rest[0]) || len(av.numbers) != len(strings.Split(base, ".")) {
+				return av, false
+			}
+			av.letter = rest
+		}
+	}
+
+	if suffixes != "" {
+		for _, suffix := range strings.Split(suffixes, "_") {
+			name, number := takeAlpha(suffix)
+			rank, ok := apkSuffixRanks[name]
+			if !ok || strings.TrimLeft(number, "0123456789") != "" {
+				return av, false
+			}
+			av.suffixes = append(av.suffixes, apkSuffix{rank: rank, number: number})
+		}
+	}
+
+	return av, true
+}
+
+// compareAPK compara versões do Alpine; o que não segue o formato do apk
+// cai na comparação genérica.
+func compareAPK(a, b string) int {
+	va, okA := parseAPKVersion(a)
+	vb, okB := parseAPKVersion(b)
+	if !okA || !okB {
+		return compareGeneric(a, b)
+	}
+
+	for i := 0; i < min(len(va.numbers), len(vb.numbers)); i++ {
+		if c := compareNumeric(va.numbers[i], vb.numbers[i]); c != 0 {
+			return c
+		}
+	}
+	if len(va.numbers) != len(vb.numbers) {
+		return sign(len(va.numbers) - len(vb.numbers))
+	}
+
+	if c := strings.Compare(va.letter, vb.letter); c != 0 {
+		return c
+	}
+
+	for i := 0; i < max(len(va.suffixes), len(vb.suffixes)); i++ {
+		var x, y apkSuffix
+		if i < len(va.suffixes) {
+			x = va.suffixes[i]
+		}
+		if i < len(vb.suffixes) {
+			y = vb.suffixes[i]
+		}
+		if x.rank != y.rank {
+			return sign(x.rank - y.rank)
+		}
+		if c := compareNumeric(x.number, y.number); c != 0 {
+			return c
+		}
+	}
+
+	return compareNumeric(va.release, vb.release)
+}
+
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return sign(len(a) - len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+func takeDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func takeAlpha(s string) (string, string) {
+	i := 0
+	for i < len(s) && isAlpha(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isAlpha(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
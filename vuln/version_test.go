@@ -0,0 +1,96 @@
+package vuln
+
+import "testing"
+
+type versionCase struct {
+	a, b string
+	want int
+}
+
+func checkCompare(t *testing.T, name string, compare func(a, b string) int, cases []versionCase) {
+	t.Helper()
+	for _, c := range cases {
+		if got := compare(c.a, c.b); got != c.want {
+			t.Errorf("%s(%q, %q) = %d, esperado %d", name, c.a, c.b, got, c.want)
+		}
+		if got := compare(c.b, c.a); got != -c.want {
+			t.Errorf("%s(%q, %q) = %d, esperado %d", name, c.b, c.a, got, -c.want)
+		}
+	}
+}
+
+func TestCompareGeneric(t *testing.T) {
+	checkCompare(t, "compareGeneric", compareGeneric, []versionCase{
+		{"2.31.0", "2.4", 1},
+		{"1.0", "1.0.0", 0},
+		{"1.0rc1", "1.0", -1},
+		{"1.0a1", "1.0b1", -1},
+		{"1.0.dev1", "1.0a1", -1},
+		{"1.0.post1", "1.0", 1},
+		{"1.0.post1", "1.0.1", -1},
+		{"1.0.1", "1.0rc1", 1},
+		{"1.0-SNAPSHOT", "1.0", -1},
+		{"5.3.0.Final", "5.3.0", 0},
+		{"2.3.1 (r1234)", "2.3.1", 1},
+		{"1.0!", "1.0", 0},
+		{"1.0!", "1.0.1", -1},
+		{"", "1.0", -1},
+		{"", "", 0},
+	})
+}
+
+func TestCompareAPK(t *testing.T) {
+	checkCompare(t, "compareAPK", compareAPK, []versionCase{
+		{"9.3_p2-r0", "9.3-r0", 1},
+		{"1.2.3-r1", "1.2.3-r10", -1},
+		{"1.2.4-r2", "1.2.3-r10", 1},
+		{"1.0_rc1-r0", "1.0-r0", -1},
+		{"1.0_alpha2", "1.0_beta1", -1},
+		{"1.0_git20230101", "1.0", 1},
+		{"1.0_git20230101", "1.0_p1", -1},
+		{"1.0a", "1.0", 1},
+		{"1.0.1", "1.0", 1},
+		{"3.1.4-r5", "3.1.4-r5", 0},
+	})
+}
+
+func TestCompareDpkg(t *testing.T) {
+	checkCompare(t, "compareDpkg", compareDpkg, []versionCase{
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~~", "1.0~", -1},
+		{"1:0.9", "2.0", 1},
+		{"3.0.11-1~deb12u2", "3.0.11-1~deb12u1", 1},
+		{"3.0.11-1~deb12u2", "3.0.11-1", -1},
+		{"1.2.3-1", "1.2.3-1", 0},
+		{"1.10", "1.9", 1},
+		{"1.0a", "1.0+", -1},
+		{"5.2.15-2+b9", "5.2.15-2", 1},
+		{"0:1.0", "1.0", 0},
+	})
+}
+
+func TestCompareRPM(t *testing.T) {
+	checkCompare(t, "compareRPM", compareRPM, []versionCase{
+		{"1.0-1.el9", "1.0-2.el9", -1},
+		{"2:1.0", "1:9.9", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0^git1", "1.0", 1},
+		{"1.0^git1", "1.0.1", -1},
+		{"1.0a", "1.0.1", -1},
+		{"3.0.7-24.el9", "3.0.7-25.el9_3", -1},
+		{"1.0", "1.0-5", 0},
+		{"1.010", "1.9", 1},
+	})
+}
+
+func TestCompareSemver(t *testing.T) {
+	checkCompare(t, "compareSemver", compareSemver, []versionCase{
+		{"v1.2.3", "1.2.10", -1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-rc.1", "1.0.0-rc.11", -1},
+		{"1.0.0+build5", "1.0.0", 0},
+		{"go1.21.3", "1.21.4", -1},
+		{"1.22", "1.22.0", 0},
+	})
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"monitoramento/watchdog"
+)
+
+const watchdogSectionPrefix = "watchdog:"
+
+// newWatchdog monta as regras a partir das seções [watchdog:<nome>] do
+// config.ini e recupera o estado salvo em watchdog_state_file.
+func newWatchdog(sections map[string]map[string]string) *watchdog.Watchdog {
+	stateFile := sections[""]["watchdog_state_file"]
+	// Numa execução única (cron, por exemplo) sem arquivo de estado o
+	// backoff e o contador de reinícios recomeçariam a cada execução, e o
+	// comando rodaria toda vez.
+	singleShot := configSeconds(sections[""], "collection_interval", 0) <= 0 && stateFile == ""
+
+	var names []string
+	for section := range sections {
+		if strings.HasPrefix(section, watchdogSectionPrefix) {
+			names = append(names, section)
+		}
+	}
+	sort.Strings(names)
+
+	var rules []watchdog.Rule
+	for _, section := range names {
+		config := sections[section]
+		rule := watchdog.Rule{
+			Name:           strings.TrimPrefix(section, watchdogSectionPrefix),
+			Process:        config["process"],
+			PIDFile:        config["pidfile"],
+			RestartCommand: config["restart_command"],
+			Backoff:        configSeconds(config, "restart_backoff", 10*time.Second),
+			MaxBackoff:     configSeconds(config, "restart_max_backoff", 10*time.Minute),
+		}
+
+		if pattern := config["cmdline"]; pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				log.Printf("Regex inválida em [%s]: %v", section, err)
+				continue
+			}
+			rule.Cmdline = re
+		}
+
+		if rule.Process == "" && rule.Cmdline == nil && rule.PIDFile == "" {
+			log.Printf("Seção [%s] ignorada: informe process, cmdline ou pidfile", section)
+			continue
+		}
+
+		if singleShot && rule.RestartCommand != "" {
+			log.Printf("Reinício de [%s] desativado: em execução única é preciso informar watchdog_state_file", section)
+			rule.RestartCommand = ""
+		}
+
+		rules = append(rules, rule)
+	}
+
+	w := watchdog.New(rules)
+	if stateFile != "" {
+		if err := w.Load(stateFile); err != nil && !os.IsNotExist(err) {
+			log.Printf("Erro ao carregar estado do watchdog: %v", err)
+		}
+	}
+	return w
+}
+
+func (a *agent) saveWatchdog() {
+	if stateFile := a.config["watchdog_state_file"]; stateFile != "" {
+		if err := a.watchdog.Save(stateFile); err != nil {
+			log.Printf("Erro ao salvar estado do watchdog: %v", err)
+		}
+	}
+}
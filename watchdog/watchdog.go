@@ -0,0 +1,212 @@
+package watchdog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"monitoramento/software"
+)
+
+// Rule descreve um processo que precisa estar sempre rodando. Os critérios
+// preenchidos precisam bater todos: nome exato, regex sobre a linha de
+// comando e/ou o PID gravado em PIDFile.
+type Rule struct {
+	Name           string
+	Process        string
+	Cmdline        *regexp.Regexp
+	PIDFile        string
+	RestartCommand string
+	Backoff        time.Duration
+	MaxBackoff     time.Duration
+}
+
+type Status struct {
+	Name        string    `json:"name"`
+	Up          bool      `json:"up"`
+	PIDs        []int     `json:"pids"`
+	DownSince   time.Time `json:"down_since"`
+	Restarts    int       `json:"restarts"`
+	LastRestart time.Time `json:"last_restart"`
+	Restarting  bool      `json:"restarting,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// state é o que sobrevive entre ciclos e, com Save/Load, entre execuções.
+type state struct {
+	Status      Status        `json:"status"`
+	Backoff     time.Duration `json:"backoff"`
+	NextRestart time.Time     `json:"next_restart"`
+}
+
+type Watchdog struct {
+	rules  []Rule
+	mu     sync.Mutex
+	states map[string]*state
+	wg     sync.WaitGroup
+}
+
+const restartTimeout = 30 * time.Second
+
+func New(rules []Rule) *Watchdog {
+	w := &Watchdog{rules: rules, states: make(map[string]*state)}
+	for _, rule := range rules {
+		w.states[rule.Name] = &state{Status: Status{Name: rule.Name}, Backoff: rule.Backoff}
+	}
+	return w
+}
+
+// Load recupera contadores e backoff salvos por Save. Regras que não
+// existem mais na configuração são ignoradas.
+func (w *Watchdog) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var saved map[string]*state
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for name, st := range saved {
+		if current, ok := w.states[name]; ok && st != nil {
+			st.Status.Name = name
+			st.Status.Restarting = false
+			*current = *st
+		}
+	}
+	return nil
+}
+
+func (w *Watchdog) Save(path string) error {
+	w.mu.Lock()
+	data, err := json.Marshal(w.states)
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Wait espera os comandos de reinício em andamento terminarem.
+func (w *Watchdog) Wait() {
+	w.wg.Wait()
+}
+
+// Check confere cada regra contra a lista de processos do ciclo e, para os
+// que sumiram, dispara o comando de reinício em segundo plano respeitando o
+// backoff: a espera dobra a cada tentativa até MaxBackoff e volta ao início
+// quando o processo reaparece. O resultado do comando aparece no ciclo
+// seguinte.
+func (w *Watchdog) Check(processes []software.Process, now time.Time) []Status {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var statuses []Status
+	for _, rule := range w.rules {
+		st := w.states[rule.Name]
+		st.Status.PIDs = matchProcesses(rule, processes)
+		st.Status.Up = len(st.Status.PIDs) > 0
+
+		if st.Status.Up {
+			st.Status.DownSince = time.Time{}
+			st.Backoff = rule.Backoff
+			st.NextRestart = time.Time{}
+		} else {
+			if st.Status.DownSince.IsZero() {
+				st.Status.DownSince = now
+			}
+			if rule.RestartCommand != "" && !st.Status.Restarting && !now.Before(st.NextRestart) {
+				st.Status.Restarts++
+				st.Status.LastRestart = now
+				st.Status.LastError = ""
+				st.Status.Restarting = true
+
+				st.NextRestart = now.Add(st.Backoff)
+				st.Backoff *= 2
+				if rule.MaxBackoff > 0 && st.Backoff > rule.MaxBackoff {
+					st.Backoff = rule.MaxBackoff
+				}
+
+				w.wg.Add(1)
+				go w.restart(rule.RestartCommand, st)
+			}
+		}
+
+		statuses = append(statuses, st.Status)
+	}
+
+	return statuses
+}
+
+func (w *Watchdog) restart(command string, st *state) {
+	defer w.wg.Done()
+	err := runRestart(command)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	st.Status.Restarting = false
+	if err != nil {
+		st.Status.LastError = err.Error()
+	}
+}
+
+func matchProcesses(rule Rule, processes []software.Process) []int {
+	pidFromFile := 0
+	if rule.PIDFile != "" {
+		data, err := os.ReadFile(rule.PIDFile)
+		if err != nil {
+			return nil
+		}
+		pidFromFile, err = strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil
+		}
+	}
+
+	var pids []int
+	for _, p := range processes {
+		if pidFromFile != 0 && p.PID != pidFromFile {
+			continue
+		}
+		if rule.Process != "" && p.Name != rule.Process {
+			continue
+		}
+		if rule.Cmdline != nil && !rule.Cmdline.MatchString(p.Cmdline) {
+			continue
+		}
+		pids = append(pids, p.PID)
+	}
+	return pids
+}
+
+func runRestart(command string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), restartTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	// Sem isso um comando que deixa o processo em segundo plano com a saída
+	// aberta ficaria preso até o timeout.
+	cmd.WaitDelay = time.Second
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("erro ao executar %q: %v: %s", command, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package watchdog
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitoramento/software"
+)
+
+// "exit 3" funciona tanto no sh quanto no cmd.
+func failingRule() Rule {
+	return Rule{
+		Name:           "kiosk",
+		Process:        "chromium",
+		RestartCommand: "exit 3",
+		Backoff:        10 * time.Second,
+		MaxBackoff:     time.Minute,
+	}
+}
+
+func TestCheckRestartsInBackground(t *testing.T) {
+	w := New([]Rule{failingRule()})
+	now := time.Now()
+
+	statuses := w.Check(nil, now)
+	if st := statuses[0]; st.Up || st.Restarts != 1 || !st.Restarting {
+		t.Fatalf("esperado reinício disparado, obtido %+v", st)
+	}
+
+	w.Wait()
+	st := w.Check(nil, now.Add(time.Second))[0]
+	if st.Restarting || st.Restarts != 1 || st.LastError == "" {
+		t.Errorf("esperado erro do reinício e nenhuma nova tentativa dentro do backoff, obtido %+v", st)
+	}
+
+	st = w.Check([]software.Process{{PID: 42, Name: "chromium"}}, now.Add(2*time.Second))[0]
+	if !st.Up || len(st.PIDs) != 1 || !st.DownSince.IsZero() {
+		t.Errorf("esperado processo de pé, obtido %+v", st)
+	}
+}
+
+func TestSaveLoadKeepsBackoff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchdog.json")
+	now := time.Now()
+
+	w := New([]Rule{failingRule()})
+	w.Check(nil, now)
+	w.Wait()
+	if err := w.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Uma nova execução (cron, por exemplo) dentro do backoff não reinicia.
+	w = New([]Rule{failingRule(), {Name: "outro", Process: "outro"}})
+	if err := w.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	statuses := w.Check(nil, now.Add(5*time.Second))
+	if st := statuses[0]; st.Restarts != 1 || st.Restarting || st.LastError == "" || !st.DownSince.Equal(now) {
+		t.Errorf("estado não foi recuperado: %+v", st)
+	}
+
+	// Passado o backoff, tenta de novo e a espera dobra.
+	if st := w.Check(nil, now.Add(10*time.Second))[0]; st.Restarts != 2 {
+		t.Errorf("esperado segundo reinício após o backoff, obtido %+v", st)
+	}
+	w.Wait()
+	if st := w.Check(nil, now.Add(25*time.Second))[0]; st.Restarts != 2 {
+		t.Errorf("esperado backoff de 20s após o segundo reinício, obtido %+v", st)
+	}
+}